@@ -46,7 +46,5 @@ func main() {
 		os.Exit(1)
 	}()
 
-	go cmd.LogTask()
-
 	cmd.Execute()
 }
@@ -21,6 +21,9 @@
 package cmd_test
 
 import (
+	"bytes"
+	"io"
+	"os"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -31,3 +34,47 @@ func TestCmd(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Havener Command Package Suite")
 }
+
+func captureStdout(f func()) string {
+	r, w, err := os.Pipe()
+	Expect(err).ToNot(HaveOccurred())
+
+	tmp := os.Stdout
+	defer func() {
+		os.Stdout = tmp
+	}()
+
+	// Read concurrently to not block writes once the pipe buffer is full
+	var buf bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(&buf, r)
+	}()
+
+	os.Stdout = w
+	f()
+	w.Close()
+
+	<-done
+	return buf.String()
+}
+
+func withStdin(input string, f func()) {
+	r, w, err := os.Pipe()
+	Expect(err).ToNot(HaveOccurred())
+
+	tmp := os.Stdin
+	defer func() {
+		os.Stdin = tmp
+		r.Close()
+	}()
+
+	go func() {
+		defer w.Close()
+		_, _ = io.WriteString(w, input)
+	}()
+
+	os.Stdin = r
+	f()
+}
@@ -21,13 +21,23 @@
 package cmd
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"text/template"
 
 	"github.com/mattn/go-isatty"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
+// stdinDefaultLimit is the default maximum size of the standard input that is
+// buffered in order to pass it to multiple targets
+const stdinDefaultLimit = "16Mi"
+
 func combineErrorsFromChannel(context string, c chan error) error {
 	var errs []error
 	for err := range c {
@@ -48,3 +58,67 @@ func combineErrorsFromChannel(context string, c chan error) error {
 func isTerminal(fd uintptr) bool { return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd) }
 
 func isStdinTerminal() bool { return isTerminal(os.Stdin.Fd()) }
+
+// replayableInput reads the input once and returns a function that provides a
+// new reader with the same content for each target of a distributed command
+// execution. Since the input is kept in memory, it must not exceed the limit.
+func replayableInput(in io.Reader, limit string) (func() io.Reader, error) {
+	quantity, err := resource.ParseQuantity(limit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid standard input limit %q: %w", limit, err)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(in, quantity.Value()+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read standard input: %w", err)
+	}
+
+	if int64(len(data)) > quantity.Value() {
+		return nil, fmt.Errorf("standard input exceeds the limit of %s for the distributed command execution, use --stdin-limit to increase it",
+			humanReadableSize(quantity.Value()),
+		)
+	}
+
+	return func() io.Reader { return bytes.NewReader(data) }, nil
+}
+
+// commandTemplateData is the data that is available in the command templates,
+// which is either the pod and container, or the node of the target
+type commandTemplateData struct {
+	Cluster   string
+	Pod       *corev1.Pod
+	Container string
+	Node      *corev1.Node
+}
+
+// commandTemplate is a command with each argument being a Go template
+type commandTemplate []*template.Template
+
+func newCommandTemplate(command []string) (commandTemplate, error) {
+	var result = make(commandTemplate, len(command))
+	for i, arg := range command {
+		tmpl, err := template.New(fmt.Sprintf("arg%d", i)).Option("missingkey=error").Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse command argument %q as template: %w", arg, err)
+		}
+
+		result[i] = tmpl
+	}
+
+	return result, nil
+}
+
+// expand returns the command with each argument expanded using the data
+func (t commandTemplate) expand(data commandTemplateData) ([]string, error) {
+	var result = make([]string, len(t))
+	for i, tmpl := range t {
+		var buf strings.Builder
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("failed to expand command template: %w", err)
+		}
+
+		result[i] = buf.String()
+	}
+
+	return result, nil
+}
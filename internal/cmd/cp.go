@@ -0,0 +1,393 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/gonvenience/bunt"
+	"github.com/homeport/havener/pkg/havener"
+	"github.com/spf13/cobra"
+)
+
+const copyDefaultMaxParallel = 10
+
+// copyUploadScript extracts the archive from stdin to the destination path.
+// In case the destination is a directory, or ends with a slash, the archive
+// content is placed into it. Otherwise, the archive entry is renamed to the
+// destination path using a temporary directory next to it.
+const copyUploadScript = `set -e
+if [ -d "$1" ] || [ "${1%/}" != "$1" ]; then
+  mkdir -p "$1"
+  tar -x -z -f - -C "$1"
+else
+  parent="$(dirname "$1")"
+  mkdir -p "$parent"
+  tmp="$(mktemp -d "$parent/.havener-cp.XXXXXX")"
+  trap 'rm -rf "$tmp"' EXIT
+  tar -x -z -f - -C "$tmp"
+  rm -rf "$1"
+  mv "$tmp/$2" "$1"
+fi`
+
+// copyDownloadScript writes an archive of the source path to stdout
+const copyDownloadScript = `cd "$(dirname "$1")" && tar -c -z -f - "$(basename "$1")"`
+
+var copyCmdSettings struct {
+	maxParallel int
+	image       string
+	timeout     time.Duration
+}
+
+// copyCmd represents the cp command
+var copyCmd = &cobra.Command{
+	Use:   "cp [flags] <source> <destination>",
+	Short: "Copy files and directories between local machine and pods or nodes",
+	Long: bunt.Sprintf(`*Copy files and directories between local machine and pods or nodes*
+
+One of source and destination is a local path, the other one refers to a
+path on pods or nodes:
+
+- _[[<namespace>/]<pod>[/container]]:<path>_ for pods, which supports the
+  same notation as the _pod-exec_ command, including _all_, wildcards, and
+  comma separated lists
+- _node:<node>[,<node>,...]:<path>_ for nodes, including _all_
+
+For example, _havener cp ns/pod/container:/var/vcap/jobs ./jobs_ downloads the
+jobs directory, and _havener cp ./cert.pem node:worker-3:/tmp/_ uploads the
+certificate into the tmp directory of the node.
+
+In case the destination is an existing directory or ends with a slash, the
+source is copied into it. Otherwise, the source is copied to the destination
+path. When downloading from more than one target, each target gets its own
+sub-directory in the destination directory, for example
+_<namespace>/<pod>/<container>_ or _<node>_.
+
+The pods and nodes need to provide _/bin/sh_ and _tar_ for the copy.
+`),
+	Args:          cobra.ExactArgs(2),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hvnrs, err := newHaveners(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("unable to get access to cluster: %w", err)
+		}
+
+		return copyFiles(hvnrs, args[0], args[1])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(copyCmd)
+
+	copyCmd.Flags().SortFlags = false
+	copyCmd.Flags().IntVar(&copyCmdSettings.maxParallel, "max-parallel", copyDefaultMaxParallel, "number of parallel copies (value less or equal than zero means unlimited)")
+	copyCmd.Flags().StringVar(&copyCmdSettings.image, "image", nodeExecDefaultImage, "container image used for the helper pod to access nodes")
+	copyCmd.Flags().DurationVar(&copyCmdSettings.timeout, "timeout", nodeExecDefaultTimeout, "timeout for the setup of the helper pod to access nodes")
+}
+
+// copyLocation is either a local path, or a path on pods or nodes
+type copyLocation struct {
+	remote  bool
+	node    bool
+	targets string
+	path    string
+}
+
+func parseCopyLocation(arg string) (copyLocation, error) {
+	// Paths that start like a local path are local, even if they contain a colon
+	if filepath.IsAbs(arg) || strings.HasPrefix(arg, ".") {
+		return copyLocation{path: arg}, nil
+	}
+
+	targets, remotePath, ok := strings.Cut(arg, ":")
+	if !ok {
+		return copyLocation{path: arg}, nil
+	}
+
+	var node bool
+	if targets == "node" {
+		if targets, remotePath, ok = strings.Cut(remotePath, ":"); !ok {
+			return copyLocation{}, fmt.Errorf("invalid node location %q, expected node:<node>:<path>", arg)
+		}
+
+		node = true
+	}
+
+	if targets == "" || remotePath == "" {
+		return copyLocation{}, fmt.Errorf("invalid location %q, both target and path are required", arg)
+	}
+
+	return copyLocation{remote: true, node: node, targets: targets, path: remotePath}, nil
+}
+
+// copyTarget is a pod container or a node to copy files from or to
+type copyTarget struct {
+	hvnr      havener.Havener
+	pod       *corev1.Pod
+	container string
+	node      *corev1.Node
+}
+
+func (t copyTarget) outputTarget() OutputTarget {
+	if t.node != nil {
+		return OutputTarget{Node: t.node.Name}
+	}
+
+	return OutputTarget{Namespace: t.pod.Namespace, Pod: t.pod.Name, Container: t.container}
+}
+
+func (t copyTarget) exec(execConfig havener.ExecConfig) error {
+	var err error
+	if t.node != nil {
+		err = t.hvnr.NodeExec(*t.node, newNodeExecHelperPodConfig(copyCmdSettings.image, copyCmdSettings.timeout), execConfig)
+	} else {
+		err = t.hvnr.PodExec(t.pod, t.container, execConfig)
+	}
+
+	// Include the error output of the remote command, since it contains the
+	// actual reason, for example a missing file
+	var execErr *havener.ExecError
+	if errors.As(err, &execErr) && strings.TrimSpace(execErr.Stderr) != "" {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(execErr.Stderr))
+	}
+
+	return err
+}
+
+func lookupCopyTargets(hvnrs []havener.Havener, location copyLocation) ([]copyTarget, error) {
+	var targets []copyTarget
+	for _, hvnr := range hvnrs {
+		if location.node {
+			nodes, err := lookupNodesByName(hvnr, location.targets, havener.ListFilter{})
+			if err != nil {
+				return nil, err
+			}
+
+			for i := range nodes {
+				targets = append(targets, copyTarget{hvnr: hvnr, node: &nodes[i]})
+			}
+
+			continue
+		}
+
+		pods, err := lookupPodsByName(hvnr, location.targets, havener.ListFilter{})
+		if err != nil {
+			return nil, err
+		}
+
+		for pod, containers := range pods {
+			for _, container := range containers {
+				targets = append(targets, copyTarget{hvnr: hvnr, pod: pod, container: container})
+			}
+		}
+	}
+
+	switch {
+	case len(targets) == 0 && location.node:
+		return nil, fmt.Errorf("no node matches %s", location.targets)
+
+	case len(targets) == 0:
+		return nil, podNotFoundError(hvnrs, location.targets)
+	}
+
+	return targets, nil
+}
+
+func copyFiles(hvnrs []havener.Havener, source string, destination string) error {
+	src, err := parseCopyLocation(source)
+	if err != nil {
+		return err
+	}
+
+	dst, err := parseCopyLocation(destination)
+	if err != nil {
+		return err
+	}
+
+	if src.remote == dst.remote {
+		return fmt.Errorf("exactly one of source and destination needs to be a pod or node location")
+	}
+
+	var remote = src
+	if dst.remote {
+		remote = dst
+	}
+
+	targets, err := lookupCopyTargets(hvnrs, remote)
+	if err != nil {
+		return err
+	}
+
+	var copyTo func(copyTarget) error
+	switch {
+	case dst.remote:
+		// Use the absolute path, so that a source like . has a proper name
+		path, err := filepath.Abs(src.path)
+		if err != nil {
+			return err
+		}
+
+		var archive bytes.Buffer
+		if err := havener.CreateArchive(&archive, path, filepath.Base(path)); err != nil {
+			return err
+		}
+
+		copyTo = func(target copyTarget) error {
+			return uploadArchive(target, archive.Bytes(), filepath.Base(path), dst.path)
+		}
+
+	default:
+		copyTo = func(target copyTarget) error {
+			var destination = dst.path
+			if len(targets) > 1 {
+				destination = outputFilePath(dst.path, clusterOf(target.hvnr, len(hvnrs) > 1), target.outputTarget()) + string(os.PathSeparator)
+			}
+
+			return downloadArchive(target, src.path, destination)
+		}
+	}
+
+	if len(targets) == 1 {
+		return copyTo(targets[0])
+	}
+
+	if copyCmdSettings.maxParallel <= 0 {
+		copyCmdSettings.maxParallel = len(targets)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		queue     = make(chan int, len(targets))
+		summaries = make([]execSummary, len(targets))
+	)
+
+	for i := range targets {
+		queue <- i
+	}
+	close(queue)
+
+	for range min(copyCmdSettings.maxParallel, len(targets)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				var start = time.Now()
+				var err = copyTo(targets[i])
+				summaries[i] = execSummary{
+					cluster: clusterOf(targets[i].hvnr, len(hvnrs) > 1),
+					target:  targets[i].outputTarget(),
+					result:  havener.ExecResult{Duration: time.Since(start)},
+					err:     err,
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err := printExecSummary(summaries); err != nil {
+		return err
+	}
+
+	return exitWithCode(summaryExitCode(summaries))
+}
+
+// uploadArchive extracts the archive on the target, see copyUploadScript
+func uploadArchive(target copyTarget, archive []byte, name string, destination string) error {
+	return target.exec(havener.ExecConfig{
+		Command: []string{"/bin/sh", "-c", copyUploadScript, "sh", destination, name},
+		Stdin:   bytes.NewReader(archive),
+		Stdout:  io.Discard,
+		Stderr:  io.Discard,
+	})
+}
+
+// downloadArchive copies the source path of the target to the destination,
+// which is either a directory to copy into, or the new local path
+func downloadArchive(target copyTarget, source string, destination string) error {
+	var directory = destination
+	var rename bool
+	if info, err := os.Stat(destination); !strings.HasSuffix(destination, string(os.PathSeparator)) && (err != nil || !info.IsDir()) {
+		if err := os.MkdirAll(filepath.Dir(destination), os.FileMode(0755)); err != nil {
+			return err
+		}
+
+		tmp, err := os.MkdirTemp(filepath.Dir(destination), ".havener-cp-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+
+		directory, rename = tmp, true
+	}
+
+	read, write := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := target.exec(havener.ExecConfig{
+			Command: []string{"/bin/sh", "-c", copyDownloadScript, "sh", source},
+			Stdout:  write,
+			Stderr:  io.Discard,
+		})
+
+		_ = write.CloseWithError(err)
+		done <- err
+	}()
+
+	// Read the remaining data in any case to not block the command execution
+	extractErr := havener.ExtractArchive(read, directory)
+	if extractErr != nil {
+		_ = read.CloseWithError(extractErr)
+	} else {
+		_, _ = io.Copy(io.Discard, read)
+	}
+
+	if err := <-done; err != nil {
+		return err
+	}
+
+	if extractErr != nil {
+		return fmt.Errorf("failed to extract files from %s: %w", target.outputTarget(), extractErr)
+	}
+
+	if rename {
+		if err := os.RemoveAll(destination); err != nil {
+			return err
+		}
+
+		return os.Rename(filepath.Join(directory, path.Base(source)), destination)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gonvenience/bunt"
+	. "github.com/homeport/havener/internal/cmd"
+
+	"github.com/homeport/havener/pkg/havener"
+	"github.com/homeport/havener/pkg/havener/havenertest"
+)
+
+var _ = Describe("cp", func() {
+	var (
+		hvnr   *havenertest.Havener
+		local  string
+		remote string
+	)
+
+	BeforeEach(func() {
+		SetColorSettings(OFF, OFF)
+
+		hvnr = havenertest.NewHavener(
+			exampleNamespace("default"),
+			examplePod("default", "api-0", "api"),
+			examplePod("default", "api-1", "api"),
+			exampleNode("node-1"),
+		)
+
+		// Commands run locally, where the remote file system is a directory
+		local, remote = GinkgoT().TempDir(), GinkgoT().TempDir()
+		hvnr.Exec = func(_ havenertest.ExecTarget, execConfig havener.ExecConfig) error {
+			return runLocally(execConfig)
+		}
+
+		Expect(os.MkdirAll(filepath.Join(remote, "jobs", "api"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(remote, "jobs", "api", "monit"), []byte("check process api"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(local, "cert.pem"), []byte("certificate"), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		SetColorSettings(AUTO, AUTO)
+	})
+
+	It("should upload a file into a directory of a pod", func() {
+		Expect(CopyFiles([]havener.Havener{hvnr}, filepath.Join(local, "cert.pem"), "default/api-0:"+remote+"/certs/")).To(Succeed())
+		Expect(os.ReadFile(filepath.Join(remote, "certs", "cert.pem"))).To(BeEquivalentTo("certificate"))
+	})
+
+	It("should upload a file to a new path on a node", func() {
+		Expect(CopyFiles([]havener.Havener{hvnr}, filepath.Join(local, "cert.pem"), "node:node-1:"+remote+"/tls.crt")).To(Succeed())
+		Expect(os.ReadFile(filepath.Join(remote, "tls.crt"))).To(BeEquivalentTo("certificate"))
+		Expect(hvnr.Executions()).To(Equal([]string{"node-1"}))
+	})
+
+	It("should upload the current directory to a new path of a pod", func() {
+		previous, err := os.Getwd()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.Chdir(local)).To(Succeed())
+		DeferCleanup(os.Chdir, previous)
+
+		Expect(CopyFiles([]havener.Havener{hvnr}, ".", "default/api-0:"+remote+"/uploaded")).To(Succeed())
+		Expect(os.ReadFile(filepath.Join(remote, "uploaded", "cert.pem"))).To(BeEquivalentTo("certificate"))
+
+		Expect(CopyFiles([]havener.Havener{hvnr}, "./", "default/api-0:"+remote+"/again")).To(Succeed())
+		Expect(os.ReadFile(filepath.Join(remote, "again", "cert.pem"))).To(BeEquivalentTo("certificate"))
+	})
+
+	It("should push a file to all matching pods", func() {
+		captureStdout(func() {
+			Expect(CopyFiles([]havener.Havener{hvnr}, filepath.Join(local, "cert.pem"), "default/api-*:"+remote+"/")).To(Succeed())
+		})
+
+		Expect(hvnr.Executions()).To(ConsistOf("default/api-0/api", "default/api-1/api"))
+	})
+
+	It("should download a directory to a new local path", func() {
+		Expect(CopyFiles([]havener.Havener{hvnr}, "default/api-0/api:"+remote+"/jobs", filepath.Join(local, "downloaded"))).To(Succeed())
+		Expect(os.ReadFile(filepath.Join(local, "downloaded", "api", "monit"))).To(BeEquivalentTo("check process api"))
+	})
+
+	It("should download into per-target sub-directories for more than one target", func() {
+		captureStdout(func() {
+			Expect(CopyFiles([]havener.Havener{hvnr}, "default/api-*:"+remote+"/jobs", local)).To(Succeed())
+		})
+
+		for _, pod := range []string{"api-0", "api-1"} {
+			Expect(os.ReadFile(filepath.Join(local, "default", pod, "api", "jobs", "api", "monit"))).To(BeEquivalentTo("check process api"))
+		}
+	})
+
+	It("should report the reason in case the remote file does not exist", func() {
+		err := CopyFiles([]havener.Havener{hvnr}, "default/api-0:"+remote+"/missing", local)
+		Expect(err).To(MatchError(ContainSubstring("missing")))
+	})
+
+	It("should fail unless exactly one location is remote", func() {
+		Expect(CopyFiles([]havener.Havener{hvnr}, local, filepath.Join(local, "copy"))).To(MatchError(ContainSubstring("exactly one of source and destination")))
+		Expect(CopyFiles([]havener.Havener{hvnr}, "api-0:/tmp", "node:node-1:/tmp")).To(MatchError(ContainSubstring("exactly one of source and destination")))
+	})
+})
@@ -38,6 +38,7 @@ import (
 var namespaceFilter string
 
 type note struct {
+	cluster   string
 	time      time.Time
 	noteType  string
 	namespace string
@@ -54,12 +55,12 @@ var eventsCmd = &cobra.Command{
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		hvnr, err := havener.NewHavener(havener.WithContext(cmd.Context()), havener.WithKubeConfigPath(kubeConfig))
+		hvnrs, err := newHaveners(cmd.Context())
 		if err != nil {
 			return fmt.Errorf("unable to get access to cluster: %w", err)
 		}
 
-		return retrieveClusterEvents(hvnr)
+		return retrieveClusterEvents(hvnrs)
 	},
 }
 
@@ -69,14 +70,46 @@ func init() {
 	eventsCmd.PersistentFlags().StringVarP(&namespaceFilter, "namespace", "n", "", "Filter events for specific namespace")
 }
 
-func retrieveClusterEvents(hvnr havener.Havener) error {
+func retrieveClusterEvents(hvnrs []havener.Havener) error {
+	notes := make(chan note)
+
+	for _, hvnr := range hvnrs {
+		if err := watchClusterEvents(hvnr, clusterOf(hvnr, len(hvnrs) > 1), notes); err != nil {
+			return err
+		}
+	}
+
+	// Show the generated notes until the user stops the application
+	for note := range notes {
+		var noteColor = bunt.LightSteelBlue
+		if note.noteType == "Warning" {
+			noteColor = bunt.FireBrick
+		}
+
+		var cluster string
+		if note.cluster != "" {
+			cluster = bunt.Sprintf("LightSlateGray{%s} ", note.cluster)
+		}
+
+		bunt.Printf("DimGray{%s} %s%-7s _%s_/%s  *%s*  AntiqueWhite{%s}\n",
+			note.time,
+			cluster,
+			bunt.Style(note.noteType, bunt.Foreground(noteColor)),
+			note.namespace,
+			note.resource,
+			note.reason,
+			note.message,
+		)
+	}
+	return nil
+}
+
+func watchClusterEvents(hvnr havener.Havener, cluster string, notes chan note) error {
 	namespaces, err := hvnr.ListNamespaces()
 	if err != nil {
 		return fmt.Errorf("failed to get a list of namespaces: %w", err)
 	}
 
-	notes := make(chan note)
-
 	// Start one Go routine per namespace to watch for events
 	for i := range namespaces {
 		namespace := namespaces[i]
@@ -103,6 +136,7 @@ func retrieveClusterEvents(hvnr havener.Havener) error {
 						}
 
 						notes <- note{
+							cluster:   cluster,
 							namespace: namespace,
 							time:      data.FirstTimestamp.Time,
 							noteType:  data.Type,
@@ -116,21 +150,5 @@ func retrieveClusterEvents(hvnr havener.Havener) error {
 		}()
 	}
 
-	// Show the generated notes until the user stops the application
-	for note := range notes {
-		var noteColor = bunt.LightSteelBlue
-		if note.noteType == "Warning" {
-			noteColor = bunt.FireBrick
-		}
-
-		bunt.Printf("DimGray{%s} %-7s _%s_/%s  *%s*  AntiqueWhite{%s}\n",
-			note.time,
-			bunt.Style(note.noteType, bunt.Foreground(noteColor)),
-			note.namespace,
-			note.resource,
-			note.reason,
-			note.message,
-		)
-	}
 	return nil
 }
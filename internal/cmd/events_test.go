@@ -0,0 +1,60 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/homeport/havener/internal/cmd"
+
+	"github.com/homeport/havener/pkg/havener/havenertest"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("events", func() {
+	It("should report events that occur in the cluster", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		hvnr := havenertest.NewHavenerWithContext(ctx, "fake", exampleNamespace("default"))
+
+		notes := make(chan Note, 1)
+		Expect(WatchClusterEvents(hvnr, "", notes)).To(Succeed())
+
+		_, err := hvnr.Client().CoreV1().Events("default").Create(ctx, &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api-0.17f4a"},
+			Type:       "Warning",
+			Reason:     "BackOff",
+			Message:    "Back-off restarting failed container\n",
+		}, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		var note Note
+		Eventually(notes).Should(Receive(&note))
+		Expect(note.Resource()).To(Equal("api-0"))
+		Expect(note.Message()).To(Equal("Back-off restarting failed container"))
+	})
+})
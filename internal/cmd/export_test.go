@@ -0,0 +1,204 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"time"
+)
+
+// Export unexported functions to be used in the external test package
+
+var (
+	ExecInClusterPods  = execInClusterPods
+	ExecInClusterNodes = execInClusterNodes
+	LookupPodsByName   = lookupPodsByName
+	GeneratePodsTable  = generatePodsTable
+	WatchClusterEvents = watchClusterEvents
+	DescribeError      = describeError
+	HavenerLogger      = havenerLogger
+	CopyFiles          = copyFiles
+	TailLogs           = tailLogs
+	GrepLogs           = grepLogs
+	SelectLogProfiles  = selectLogProfiles
+)
+
+// Note is the exported variant of the event note
+type Note = note
+
+// Message returns the event message of the note
+func (n note) Message() string { return n.message }
+
+// Resource returns the resource name of the note
+func (n note) Resource() string { return n.resource }
+
+// ExitCodeOf returns the exit code the command would exit with
+func ExitCodeOf(err error) int {
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+
+	if err != nil {
+		return 1
+	}
+
+	return 0
+}
+
+// SetPodExecParallelism sets the pod-exec flags for the parallel execution
+// and returns a function to reset them
+func SetPodExecParallelism(maxParallel int, failFast bool, canary int) func() {
+	previous := podExecCmdSettings
+	podExecCmdSettings.maxParallel = maxParallel
+	podExecCmdSettings.failFast = failFast
+	podExecCmdSettings.canary = canary
+
+	return func() { podExecCmdSettings = previous }
+}
+
+// SetPodExecStdin sets the pod-exec flags for the standard input and returns
+// a function to reset them
+func SetPodExecStdin(stdin bool, limit string) func() {
+	previous := podExecCmdSettings
+	podExecCmdSettings.stdin = stdin
+	podExecCmdSettings.stdinLimit = limit
+
+	return func() { podExecCmdSettings = previous }
+}
+
+// SetExecOutput sets the output format of pod-exec and node-exec and returns
+// a function to reset it
+func SetExecOutput(format string) func() {
+	previousPod, previousNode := podExecCmdSettings.output, nodeExecCmdSettings.output
+	podExecCmdSettings.output = format
+	nodeExecCmdSettings.output = format
+
+	return func() {
+		podExecCmdSettings.output = previousPod
+		nodeExecCmdSettings.output = previousNode
+	}
+}
+
+// SetExecOutputDir sets the output directory and quiet flag of pod-exec and
+// node-exec and returns a function to reset them
+func SetExecOutputDir(dir string, quiet bool) func() {
+	previousPod, previousNode := podExecCmdSettings, nodeExecCmdSettings
+	podExecCmdSettings.outputDir, podExecCmdSettings.quiet = dir, quiet
+	nodeExecCmdSettings.outputDir, nodeExecCmdSettings.quiet = dir, quiet
+
+	return func() {
+		podExecCmdSettings = previousPod
+		nodeExecCmdSettings = previousNode
+	}
+}
+
+// SetExecTemplate enables or disables the command templates of pod-exec and
+// node-exec and returns a function to reset them
+func SetExecTemplate(enabled bool) func() {
+	previousPod, previousNode := podExecCmdSettings.template, nodeExecCmdSettings.template
+	podExecCmdSettings.template = enabled
+	nodeExecCmdSettings.template = enabled
+
+	return func() {
+		podExecCmdSettings.template = previousPod
+		nodeExecCmdSettings.template = previousNode
+	}
+}
+
+// SetExecScript sets the script of pod-exec and node-exec and returns a
+// function to reset it
+func SetExecScript(path string) func() {
+	previousPod, previousNode := podExecCmdSettings.script, nodeExecCmdSettings.script
+	podExecCmdSettings.script = path
+	nodeExecCmdSettings.script = path
+
+	return func() {
+		podExecCmdSettings.script = previousPod
+		nodeExecCmdSettings.script = previousNode
+	}
+}
+
+// SetTailSettings sets the tail flags for the previous logs and the filters,
+// as well as the refresh interval, and returns a function to reset them
+func SetTailSettings(previous bool, include string, exclude string, refresh time.Duration) func() {
+	previousSettings, previousRefresh := tailCmdSettings, tailRefreshInterval
+	tailCmdSettings.previous = previous
+	tailCmdSettings.include = include
+	tailCmdSettings.exclude = exclude
+	tailRefreshInterval = refresh
+
+	return func() {
+		tailCmdSettings = previousSettings
+		tailRefreshInterval = previousRefresh
+	}
+}
+
+// SetTailFiles sets the file glob patterns of tail and returns a function to
+// reset them
+func SetTailFiles(files ...string) func() {
+	previous := tailCmdSettings.files
+	tailCmdSettings.files = files
+
+	return func() { tailCmdSettings.files = previous }
+}
+
+// SetGrepSettings sets the grep flags for the files search and the context
+// lines, using a small number of parallel jobs, and returns a function to
+// reset them
+func SetGrepSettings(files bool, context int) func() {
+	previous := grepCmdSettings
+	grepCmdSettings.files = files
+	grepCmdSettings.context = context
+	grepCmdSettings.parallel = 2
+	grepCmdSettings.timeout = 60
+
+	return func() { grepCmdSettings = previous }
+}
+
+// SetGrepProfiles sets the grep flags for the log profiles and returns a
+// function to reset them
+func SetGrepProfiles(file string, names []string) func() {
+	previous := grepCmdSettings
+	grepCmdSettings.profileFile = file
+	grepCmdSettings.profiles = names
+
+	return func() { grepCmdSettings = previous }
+}
+
+// GrepLines returns the line numbers of the search result lines of the
+// reader, where separators are zero and context lines are negative
+func GrepLines(r io.Reader, pattern string, context int) ([]int, int, error) {
+	lines, hits, err := grepLines(r, regexp.MustCompile(pattern), context)
+
+	var result []int
+	for _, line := range lines {
+		if line.match {
+			result = append(result, line.number)
+		} else {
+			result = append(result, -line.number)
+		}
+	}
+
+	return result, hits, err
+}
@@ -0,0 +1,636 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/gonvenience/bunt"
+	"github.com/homeport/havener/pkg/havener"
+	"github.com/spf13/cobra"
+)
+
+// grepFilesScript searches the files listed by the find commands for the
+// pattern in the first argument, using the number of context lines in the
+// second argument. The files are filtered with grep inside the container if
+// it is available and the pattern is not empty, otherwise all lines are sent.
+// Each file starts with a header line, and all other lines start with their
+// line number, so that the file contents cannot be mistaken for a header.
+const grepFilesScript = `pattern="$1"
+context="$2"
+
+search() {
+  n=0
+  while IFS= read -r line || [ -n "$line" ]; do
+    n=$((n + 1))
+    printf '%%d:%%s\n' "$n" "$line"
+  done <"$1"
+}
+
+if [ -n "$pattern" ]; then
+  grep -E -e "$pattern" </dev/null >/dev/null 2>&1
+  if [ $? -eq 1 ]; then
+    search() {
+      grep -n -E -C "$context" -e "$pattern" -- "$1"
+    }
+  fi
+fi
+
+cd / && (%s) | while IFS= read -r file; do
+  printf '==> /%%s <==\n' "$file"
+  search "$file" 2>/dev/null || true
+done`
+
+// grepUnportable matches the syntax of regular expressions that is either
+// not supported by grep or has a different meaning there, like \d, flags,
+// and lazy quantifiers, in which case the files are not filtered by grep
+var grepUnportable = regexp.MustCompile(`\\[[:alnum:]]|\(\?|[*+?}]\?`)
+
+// maxGrepLineLength is the length up to which lines are searched, longer
+// lines are skipped
+const maxGrepLineLength = 1024 * 1024
+
+var grepCmdSettings struct {
+	selector      string
+	fieldSelector string
+	since         time.Duration
+	files         bool
+	profiles      []string
+	profileFile   string
+	context       int
+	parallel      int
+	timeout       int
+}
+
+// grepCmd represents the grep command
+var grepCmd = &cobra.Command{
+	Use:   "grep [flags] <pattern> [[<namespace>/]<pod>[/container]]",
+	Short: "Search the logs of pods for a pattern",
+	Long: bunt.Sprintf(`*Search the logs of pods for a pattern*
+
+Searches the container logs of all pods for lines that match the regular
+expression, without downloading the logs first. The matching lines are shown
+with the pod and container name as the origin and some lines of context
+(_--context_), followed by a summary of the number of matches per pod. In
+case nothing matches, *havener* exits with exit code 1.
+
+The pods are selected using the same notation as the _pod-exec_ command,
+including _all_, wildcards, and comma separated lists, which is _all_ in case
+no pod is specified, for example _havener grep 'connection refused' ns/api-*_.
+Alternatively, a label selector (_--selector_) or field selector
+(_--field-selector_) can be used. Use _--since_ to only search recent log
+lines, for example _--since 1h_.
+
+With _--files_, the log files in the well-known locations that are also used
+by the _logs_ command are searched as well, for example the files in
+_/var/vcap/sys/log_. These files are filtered inside the containers using
+_grep -E_ where possible, otherwise they are streamed from the containers.
+Either way, they are searched with the same regular expression as the
+container logs. The files to search are defined by the log profiles, which
+can be selected using _--profile_ and _--profile-file_ in the same way as for
+the _logs_ command. Pods without a matching profile are only searched in their
+container logs.
+
+Like the _logs_ command, the search runs in parallel (_--parallel_) and is
+aborted if it does not finish in time (_--timeout_).
+`),
+	Args:          cobra.RangeArgs(1, 2),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hvnrs, err := newHaveners(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("unable to get access to cluster: %w", err)
+		}
+
+		return grepLogs(hvnrs, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(grepCmd)
+
+	grepCmd.Flags().SortFlags = false
+	grepCmd.Flags().StringVarP(&grepCmdSettings.selector, "selector", "l", "", "label selector of the pods to search, for example app=router")
+	grepCmd.Flags().StringVar(&grepCmdSettings.fieldSelector, "field-selector", "", "field selector of the pods to search, for example spec.nodeName=node-0")
+	grepCmd.Flags().DurationVar(&grepCmdSettings.since, "since", 0, "only search log lines newer than the given duration, for example 1h (default is to search all)")
+	grepCmd.Flags().BoolVar(&grepCmdSettings.files, "files", false, "also search the log files in well-known locations inside the containers")
+	grepCmd.Flags().StringSliceVar(&grepCmdSettings.profiles, "profile", []string{}, "comma separated list of log profiles to use with --files (default is to use all profiles)")
+	grepCmd.Flags().StringVar(&grepCmdSettings.profileFile, "profile-file", "", "YAML file with additional log profiles to use with --files")
+	grepCmd.Flags().IntVarP(&grepCmdSettings.context, "context", "C", 2, "number of lines of context to show around each match")
+	grepCmd.Flags().IntVar(&grepCmdSettings.parallel, "parallel", 64, "number of parallel search jobs")
+	grepCmd.Flags().IntVar(&grepCmdSettings.timeout, "timeout", 5*60, "allowed time in seconds before the search is aborted")
+}
+
+// grepLine is a line of a search result, which is either a matching line, a
+// context line, or a separator between non-adjacent lines (line number zero)
+type grepLine struct {
+	number int
+	text   string
+	match  bool
+}
+
+// grepMatcher searches lines one by one and keeps the matching lines,
+// including the given number of context lines around them
+type grepMatcher struct {
+	pattern *regexp.Regexp
+	context int
+
+	result []grepLine
+	hits   int
+	number int
+	before []grepLine
+	after  int
+	last   int
+}
+
+func (m *grepMatcher) add(line grepLine) {
+	if m.last > 0 && line.number > m.last+1 {
+		m.result = append(m.result, grepLine{})
+	}
+
+	m.result, m.last = append(m.result, line), line.number
+}
+
+// line searches the next line
+func (m *grepMatcher) line(text string) {
+	m.lineAt(m.number+1, text)
+}
+
+// skip counts the next line without searching it
+func (m *grepMatcher) skip() {
+	m.number++
+	m.before = nil
+	if m.after > 0 {
+		m.after--
+	}
+}
+
+// lineAt searches the line with the given line number, the lines in between
+// are unknown and can therefore not be used as context
+func (m *grepMatcher) lineAt(number int, text string) {
+	if number != m.number+1 {
+		m.before, m.after = nil, 0
+	}
+
+	m.number = number
+	var line = grepLine{number: number, text: text}
+
+	switch {
+	case m.pattern.MatchString(line.text):
+		line.match = true
+		m.hits++
+
+		for _, previous := range m.before {
+			m.add(previous)
+		}
+
+		m.before, m.after = nil, m.context
+		m.add(line)
+
+	case m.after > 0:
+		m.after--
+		m.add(line)
+
+	case m.context > 0:
+		if m.before = append(m.before, line); len(m.before) > m.context {
+			m.before = m.before[1:]
+		}
+	}
+}
+
+// readLines calls the function for each line of the reader, lines that are
+// longer than the maximum line length are passed as not ok without the text
+func readLines(r io.Reader, fn func(line string, ok bool)) error {
+	var (
+		reader  = bufio.NewReaderSize(r, 64*1024)
+		line    []byte
+		tooLong bool
+	)
+
+	for {
+		chunk, isPrefix, err := reader.ReadLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		if !tooLong {
+			line = append(line, chunk...)
+			tooLong = len(line) > maxGrepLineLength
+		}
+
+		if isPrefix {
+			continue
+		}
+
+		if tooLong {
+			fn("", false)
+		} else {
+			fn(string(line), true)
+		}
+
+		line, tooLong = line[:0], false
+	}
+}
+
+// grepLines reads all lines and returns the matching lines, including the
+// given number of context lines around them, and the number of matches
+func grepLines(r io.Reader, pattern *regexp.Regexp, context int) ([]grepLine, int, error) {
+	var matcher = grepMatcher{pattern: pattern, context: context}
+
+	err := readLines(r, func(line string, ok bool) {
+		if ok {
+			matcher.line(line)
+		} else {
+			matcher.skip()
+		}
+	})
+
+	return matcher.result, matcher.hits, err
+}
+
+// parseGrepLine splits a line of the grep files script into the line number
+// and the text, where grep uses a colon for matching lines and a dash for
+// context lines
+func parseGrepLine(line string) (int, string, bool) {
+	var idx = strings.IndexFunc(line, func(r rune) bool { return r < '0' || r > '9' })
+	if idx <= 0 || (line[idx] != ':' && line[idx] != '-') {
+		return 0, "", false
+	}
+
+	number, err := strconv.Atoi(line[:idx])
+	if err != nil {
+		return 0, "", false
+	}
+
+	return number, line[idx+1:], true
+}
+
+// grepFiles reads the output of the grep files script and returns the search
+// results per file, the files with matches, and the number of matches. The
+// lines are searched again with the pattern, since they are either the lines
+// around the matches of grep, or all lines of the file. Other output of grep,
+// like separators, as well as lines that are too long are ignored.
+func grepFiles(r io.Reader, pattern *regexp.Regexp, context int) (map[string][]grepLine, []string, int, error) {
+	var (
+		matcher *grepMatcher
+		file    string
+		files   []string
+		result  = map[string][]grepLine{}
+		hits    int
+	)
+
+	var done = func() {
+		if matcher != nil && matcher.hits > 0 {
+			files, result[file], hits = append(files, file), matcher.result, hits+matcher.hits
+		}
+	}
+
+	err := readLines(r, func(line string, ok bool) {
+		if !ok {
+			return
+		}
+
+		if name, found := strings.CutPrefix(line, "==> "); found && strings.HasSuffix(name, " <==") {
+			done()
+			file, matcher = strings.TrimSuffix(name, " <=="), &grepMatcher{pattern: pattern, context: context}
+			return
+		}
+
+		if number, text, ok := parseGrepLine(line); ok && matcher != nil {
+			matcher.lineAt(number, text)
+		}
+	})
+
+	done()
+	return result, files, hits, err
+}
+
+// grepMessage renders a search result line as an output message, where the
+// matching parts of matching lines are highlighted and context lines dimmed
+func grepMessage(line grepLine, pattern *regexp.Regexp) string {
+	switch {
+	case line.number == 0:
+		return bunt.Style("--", bunt.Foreground(bunt.DimGray))
+
+	case !line.match:
+		return bunt.Style(fmt.Sprintf("%d-%s", line.number, line.text), bunt.Foreground(bunt.DimGray))
+	}
+
+	var text strings.Builder
+	var offset int
+	for _, loc := range pattern.FindAllStringIndex(line.text, -1) {
+		text.WriteString(line.text[offset:loc[0]])
+		text.WriteString(bunt.Style(line.text[loc[0]:loc[1]], bunt.Foreground(bunt.Red), bunt.Bold()))
+		offset = loc[1]
+	}
+
+	text.WriteString(line.text[offset:])
+	return fmt.Sprintf("%d:%s", line.number, text.String())
+}
+
+// grepHits is the number of matches in one pod
+type grepHits struct {
+	cluster string
+	pod     string
+	logs    int
+	files   int
+}
+
+func grepLogs(hvnrs []havener.Havener, args []string) error {
+	pattern, err := regexp.Compile(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	// The log files are filtered with grep inside the containers, unless the
+	// pattern would not match the same lines there
+	var filesPattern = args[0]
+	if grepUnportable.MatchString(filesPattern) {
+		filesPattern = ""
+	}
+
+	var input = "all"
+	if len(args) > 1 {
+		input = args[1]
+	}
+
+	var filter = havener.ListFilter{
+		LabelSelector: grepCmdSettings.selector,
+		FieldSelector: grepCmdSettings.fieldSelector,
+	}
+
+	var profiles []havener.LogProfile
+	if grepCmdSettings.files {
+		if profiles, err = selectLogProfiles(grepCmdSettings.profileFile, grepCmdSettings.profiles); err != nil {
+			return err
+		}
+	}
+
+	// A task searches either the container logs, or the files that are
+	// listed by the files command of the log profile
+	type task struct {
+		hvnr         havener.Havener
+		pod          *corev1.Pod
+		container    string
+		files        bool
+		filesCommand string
+	}
+
+	var tasks []task
+	for _, hvnr := range hvnrs {
+		podMap, err := lookupPodsByName(hvnr, input, filter)
+		if err != nil {
+			return err
+		}
+
+		for pod, containers := range podMap {
+			if pod.Status.Phase == corev1.PodPending {
+				continue
+			}
+
+			profile, ok := havener.MatchingLogProfile(profiles, pod)
+			for _, container := range containers {
+				tasks = append(tasks, task{hvnr: hvnr, pod: pod, container: container})
+				if ok && len(profile.Logs) > 0 && pod.Status.Phase == corev1.PodRunning {
+					tasks = append(tasks, task{hvnr: hvnr, pod: pod, container: container, files: true, filesCommand: profile.LogFilesCommand()})
+				}
+			}
+		}
+	}
+
+	if len(tasks) == 0 {
+		return podNotFoundError(hvnrs, input)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(grepCmdSettings.timeout)*time.Second)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		sendLock sync.Mutex
+		queue    = make(chan int)
+		messages = make(chan OutputMsg)
+		printed  = make(chan struct{})
+		hits     = make([]int, len(tasks))
+		errs     = make([]error, len(tasks))
+	)
+
+	go func() {
+		defer close(printed)
+		PrintOutputMessage(messages)
+	}()
+
+	// Searches one pod container and sends all results at once, so that the
+	// lines of one origin are not mixed with the lines of other origins
+	var search = func(t task) (int, error) {
+		var target = OutputTarget{Namespace: t.pod.Namespace, Pod: t.pod.Name, Container: t.container}
+		var cluster = clusterOf(t.hvnr, len(hvnrs) > 1)
+		var results = map[string][]grepLine{}
+		var origins []string
+		var count int
+
+		if t.files {
+			r, w := io.Pipe()
+			go func() {
+				_ = w.CloseWithError(t.hvnr.PodExec(t.pod, t.container, havener.ExecConfig{
+					Command: []string{"/bin/sh", "-c", fmt.Sprintf(grepFilesScript, t.filesCommand), "sh", filesPattern, strconv.Itoa(grepCmdSettings.context)},
+					Stdout:  w,
+					Stderr:  io.Discard,
+					Context: ctx,
+				}))
+			}()
+
+			files, order, n, err := grepFiles(r, pattern, grepCmdSettings.context)
+			_ = r.Close()
+			if err != nil {
+				return 0, fmt.Errorf("failed to search files of %s: %w", target, err)
+			}
+
+			for _, file := range order {
+				origins = append(origins, target.Name()+":"+file)
+				results[target.Name()+":"+file] = files[file]
+			}
+
+			count = n
+		} else {
+			r, w := io.Pipe()
+			go func() {
+				_ = w.CloseWithError(t.hvnr.StreamContainerLogs(t.pod, t.container, havener.ContainerLogsOptions{Since: grepCmdSettings.since, Context: ctx}, w))
+			}()
+
+			lines, n, err := grepLines(r, pattern, grepCmdSettings.context)
+			_ = r.Close()
+			if err != nil {
+				return 0, err
+			}
+
+			origins = append(origins, target.Name())
+			results[target.Name()] = lines
+			count = n
+		}
+
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		for _, origin := range origins {
+			for _, line := range results[origin] {
+				messages <- OutputMsg{
+					Timestamp: time.Now(),
+					Stream:    "StdOut",
+					Cluster:   cluster,
+					Origin:    origin,
+					Target:    target,
+					Message:   grepMessage(line, pattern),
+				}
+			}
+		}
+
+		return count, nil
+	}
+
+	var parallel = grepCmdSettings.parallel
+	if parallel <= 0 || parallel > len(tasks) {
+		parallel = len(tasks)
+	}
+
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range queue {
+				hits[idx], errs[idx] = search(tasks[idx])
+			}
+		}()
+	}
+
+	for i := range tasks {
+		queue <- i
+	}
+
+	close(queue)
+	wg.Wait()
+	close(messages)
+	<-printed
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("unable to search pods: %w", fmt.Errorf("search did not finish within configured timeout"))
+	}
+
+	// Sum up the matches per pod, the files and container logs are counted
+	// separately
+	var summary []*grepHits
+	var lookup = map[string]*grepHits{}
+	for i, t := range tasks {
+		if hits[i] == 0 {
+			continue
+		}
+
+		var key = t.hvnr.ClusterName() + "/" + t.pod.Namespace + "/" + t.pod.Name
+		if _, ok := lookup[key]; !ok {
+			lookup[key] = &grepHits{cluster: clusterOf(t.hvnr, len(hvnrs) > 1), pod: t.pod.Namespace + "/" + t.pod.Name}
+			summary = append(summary, lookup[key])
+		}
+
+		if t.files {
+			lookup[key].files += hits[i]
+		} else {
+			lookup[key].logs += hits[i]
+		}
+	}
+
+	if len(summary) > 0 {
+		if err := printGrepSummary(summary); err != nil {
+			return err
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("unable to search all pods: %w", err)
+	}
+
+	if len(summary) == 0 {
+		return exitWithCode(1)
+	}
+
+	return nil
+}
+
+// printGrepSummary prints a table with the number of matches per pod, the
+// pods with the most matches first
+func printGrepSummary(summary []*grepHits) error {
+	sort.SliceStable(summary, func(i, j int) bool {
+		if total := summary[i].logs + summary[i].files; total != summary[j].logs+summary[j].files {
+			return total > summary[j].logs+summary[j].files
+		}
+
+		return summary[i].cluster+summary[i].pod < summary[j].cluster+summary[j].pod
+	})
+
+	var multiCluster = summary[0].cluster != ""
+
+	var table [][]string
+	for _, hits := range summary {
+		var row []string
+		if multiCluster {
+			row = append(row, hits.cluster)
+		}
+
+		row = append(row, hits.pod, strconv.Itoa(hits.logs))
+		if grepCmdSettings.files {
+			row = append(row, strconv.Itoa(hits.files))
+		}
+
+		table = append(table, row)
+	}
+
+	var tablehead = []string{"Pod", "Container Logs"}
+	if grepCmdSettings.files {
+		tablehead = append(tablehead, "Log Files")
+	}
+
+	if multiCluster {
+		tablehead = append([]string{"Cluster"}, tablehead...)
+	}
+
+	out, err := renderBoxWithTable("Matches per pod", tablehead, table)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(out)
+	return nil
+}
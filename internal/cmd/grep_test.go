@@ -0,0 +1,221 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gonvenience/bunt"
+	. "github.com/homeport/havener/internal/cmd"
+
+	"github.com/gonvenience/term"
+	"github.com/homeport/havener/pkg/havener"
+	"github.com/homeport/havener/pkg/havener/havenertest"
+)
+
+var _ = Describe("grep", func() {
+	BeforeEach(func() {
+		SetColorSettings(OFF, OFF)
+		term.FixedTerminalWidth = 120
+		DeferCleanup(SetGrepSettings(false, 2))
+	})
+
+	AfterEach(func() {
+		SetColorSettings(AUTO, AUTO)
+		term.FixedTerminalWidth = -1
+	})
+
+	It("should return the matching lines with the lines of context around them", func() {
+		var input = "1\n2\nmatch\n4\n5\n6\n7\n8\nmatch\n10\n11\n"
+
+		lines, hits, err := GrepLines(strings.NewReader(input), "match", 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hits).To(Equal(2))
+		Expect(lines).To(Equal([]int{-2, 3, -4, 0, -8, 9, -10}))
+
+		lines, _, err = GrepLines(strings.NewReader(input), "match", 3)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lines).To(Equal([]int{-1, -2, 3, -4, -5, -6, -7, -8, 9, -10, -11}))
+	})
+
+	It("should skip lines that are too long to search", func() {
+		var input = "1\nmatch\n" + strings.Repeat("x", 2*1024*1024) + " match\n4\nmatch\n"
+
+		lines, hits, err := GrepLines(strings.NewReader(input), "match", 1)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hits).To(Equal(2))
+		Expect(lines).To(Equal([]int{-1, 2, 0, -4, 5}))
+	})
+
+	It("should search the container logs of the pods and show a summary", func() {
+		hvnr := havenertest.NewHavener(
+			examplePod("default", "api-0", "api", "sidecar"),
+			examplePod("default", "db-0", "db"),
+		)
+
+		var err error
+		out := captureStdout(func() {
+			err = GrepLogs([]havener.Havener{hvnr}, []string{"fa+ke", "api-0"})
+		})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(ContainSubstring("api-0/api │ 1:fake logs\n"))
+		Expect(out).To(ContainSubstring("api-0/sidecar │ 1:fake logs\n"))
+		Expect(out).ToNot(ContainSubstring("db-0"))
+		Expect(out).To(ContainSubstring("Matches per pod"))
+		Expect(out).To(MatchRegexp(`default/api-0\s+2`))
+	})
+
+	It("should fail with exit code 1 in case nothing matches", func() {
+		hvnr := havenertest.NewHavener(examplePod("default", "api-0", "api"))
+
+		var err error
+		out := captureStdout(func() {
+			err = GrepLogs([]havener.Havener{hvnr}, []string{"error"})
+		})
+
+		Expect(ExitCodeOf(err)).To(Equal(1))
+		Expect(out).To(BeEmpty())
+	})
+
+	It("should search the log files in the well-known locations inside the containers", func() {
+		hvnr := havenertest.NewHavener(examplePod("default", "api-0", "api"))
+
+		var command []string
+		hvnr.Exec = func(target havenertest.ExecTarget, execConfig havener.ExecConfig) error {
+			command = execConfig.Command
+			return havenertest.Reply(
+				"==> /var/vcap/sys/log/api/api.log <==\n2-2\n3-before\n4:error 1\n5-after\n--\n8-8\n9:error 2\n"+
+					"==> /var/vcap/sys/log/api/empty.log <==\n"+
+					"==> /var/vcap/sys/log/api/other.log <==\n1:error\n2-\n",
+				"", 0)(target, execConfig)
+		}
+
+		DeferCleanup(SetGrepSettings(true, 1))
+
+		var err error
+		out := captureStdout(func() {
+			err = GrepLogs([]havener.Havener{hvnr}, []string{`error [0-9]`, "api-0"})
+		})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(command).To(HaveLen(6))
+		Expect(command[2]).To(ContainSubstring("find var/vcap/sys"))
+		Expect(command[4:]).To(Equal([]string{`error [0-9]`, "1"}))
+
+		Expect(out).To(ContainSubstring("api-0/api:/var/vcap/sys/log/api/api.log │ 3-before\n"))
+		Expect(out).To(ContainSubstring("api-0/api:/var/vcap/sys/log/api/api.log │ 4:error 1\n"))
+		Expect(out).To(ContainSubstring("api-0/api:/var/vcap/sys/log/api/api.log │ --\n"))
+		Expect(out).To(ContainSubstring("api-0/api:/var/vcap/sys/log/api/api.log │ 9:error 2\n"))
+		Expect(out).ToNot(ContainSubstring("other.log"))
+		Expect(out).To(MatchRegexp(`default/api-0\s+0\s+2`))
+	})
+
+	DescribeTable("should search the log files inside the containers with and without grep",
+		func(pattern string, filesPattern string) {
+			dir := filepath.Join(GinkgoT().TempDir(), "log")
+			Expect(os.MkdirAll(dir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "api.log"), []byte(
+				"1\nbefore\nerror 1\n==> /etc/passwd <==\nerror 2\n"+strings.Repeat("x", 2*1024*1024)+" error 3\nafter\nerror 4"), 0644)).To(Succeed())
+
+			path := filepath.Join(GinkgoT().TempDir(), "profiles.yml")
+			Expect(os.WriteFile(path, []byte(`profiles:
+- name: api
+  logs:
+  - path: `+dir+`
+`), 0644)).To(Succeed())
+
+			DeferCleanup(SetGrepSettings(true, 1))
+			DeferCleanup(SetGrepProfiles(path, []string{"api"}))
+
+			hvnr := havenertest.NewHavener(examplePod("default", "api-0", "api"))
+
+			var command []string
+			hvnr.Exec = func(_ havenertest.ExecTarget, execConfig havener.ExecConfig) error {
+				command = execConfig.Command
+				return runLocally(execConfig)
+			}
+
+			var err error
+			out := captureStdout(func() {
+				err = GrepLogs([]havener.Havener{hvnr}, []string{pattern, "api-0"})
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(command[4]).To(Equal(filesPattern))
+
+			var origin = "api-0/api:" + filepath.Join(dir, "api.log") + " │ "
+			Expect(out).To(ContainSubstring(origin + "2-before\n"))
+			Expect(out).To(ContainSubstring(origin + "3:error 1\n"))
+			Expect(out).To(ContainSubstring(origin + "4-==> /etc/passwd <==\n"))
+			Expect(out).To(ContainSubstring(origin + "5:error 2\n"))
+			Expect(out).To(ContainSubstring(origin + "--\n"))
+			Expect(out).To(ContainSubstring(origin + "7-after\n"))
+			Expect(out).To(ContainSubstring(origin + "8:error 4\n"))
+			Expect(out).ToNot(ContainSubstring("error 3"))
+			Expect(out).ToNot(ContainSubstring(origin + "1-1"))
+			Expect(out).To(MatchRegexp(`default/api-0\s+0\s+3`))
+		},
+		Entry("with grep for patterns that grep supports", `error [0-9]`, `error [0-9]`),
+		Entry("without grep for patterns that grep does not support", `error \d`, ""),
+	)
+
+	It("should search the files of the selected log profiles only in the matching pods", func() {
+		hvnr := havenertest.NewHavener(
+			examplePod("default", "api-0", "api"),
+			examplePod("db", "db-0", "db"),
+		)
+
+		var commands sync.Map
+		hvnr.Exec = func(target havenertest.ExecTarget, execConfig havener.ExecConfig) error {
+			commands.Store(target.String(), execConfig.Command[2])
+			return havenertest.Reply("==> /opt/db/logs/db.log <==\n1:error\n", "", 0)(target, execConfig)
+		}
+
+		path := filepath.Join(GinkgoT().TempDir(), "profiles.yml")
+		Expect(os.WriteFile(path, []byte(`profiles:
+- name: db
+  namespaces: [db]
+  logs:
+  - path: /opt/db/logs
+`), 0644)).To(Succeed())
+
+		DeferCleanup(SetGrepSettings(true, 0))
+		DeferCleanup(SetGrepProfiles(path, []string{"db"}))
+
+		var err error
+		out := captureStdout(func() {
+			err = GrepLogs([]havener.Havener{hvnr}, []string{"error"})
+		})
+
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hvnr.Executions()).To(Equal([]string{"db/db-0/db"}))
+
+		command, _ := commands.Load("db/db-0/db")
+		Expect(command).To(ContainSubstring("find opt/db/logs"))
+		Expect(out).To(ContainSubstring("db-0/db:/opt/db/logs/db.log │ 1:error\n"))
+	})
+})
@@ -21,25 +21,137 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
 	"github.com/gonvenience/wait"
 
 	"github.com/gonvenience/bunt"
 
-	"github.com/homeport/havener/pkg/havener"
 	"github.com/spf13/viper"
 )
 
+// logLevel indicates the priority level of the log message
+// which can be used for filtering
+type logLevel int
+
+// Supported log levels include:
+// - Off: don't print any logs
+// - Fatal: only print fatal logs
+// - Error: print error logs and previous levels
+// - Warn: print warn logs and previous levels
+// - Verbose: print verbose/info logs and previous levels
+// - Debug: print debug logs and previous levels
+// - Trace: print all logs
+const (
+	levelOff = logLevel(iota)
+	levelFatal
+	levelError
+	levelWarn
+	levelVerbose
+	levelDebug
+	levelTrace
+)
+
 var progressIndicator *wait.ProgressIndicator
 
-// LogTask processes all log messages coming
-// from havener package.
-func LogTask() {
-	channel := havener.GetLogChannel()
+// havenerLogger is the logger used for the log messages of the havener package
+var havenerLogger = slog.New(&logHandler{})
+
+// logHandler is the slog handler for the log messages of the havener package,
+// which prints the messages based on the log level command-line flags
+type logHandler struct {
+	attrs  []slog.Attr
+	prefix string
+}
+
+func (h *logHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return translateLogLevel() >= toLogLevel(level)
+}
+
+func (h *logHandler) Handle(_ context.Context, record slog.Record) error {
+	var message strings.Builder
+	message.WriteString(record.Message)
+
+	for _, attr := range h.attrs {
+		writeAttr(&message, "", attr)
+	}
+
+	record.Attrs(func(attr slog.Attr) bool {
+		writeAttr(&message, h.prefix, attr)
+		return true
+	})
+
+	log(toLogLevel(record.Level), message.String())
+	return nil
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var result = &logHandler{prefix: h.prefix}
+	result.attrs = append(result.attrs, h.attrs...)
+	for _, attr := range attrs {
+		result.attrs = append(result.attrs, slog.Attr{Key: h.prefix + attr.Key, Value: attr.Value})
+	}
+
+	return result
+}
 
-	for {
-		message := <-channel
-		targetLevel := translateLogLevel()
-		log(message, targetLevel)
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &logHandler{attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+// writeAttr writes the attribute as key=value, groups are flattened with the
+// group name as the key prefix
+func writeAttr(w *strings.Builder, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+
+		for _, groupAttr := range attr.Value.Group() {
+			writeAttr(w, prefix, groupAttr)
+		}
+
+		return
+	}
+
+	value := attr.Value.String()
+	if strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+
+	fmt.Fprintf(w, " %s%s=%s", prefix, attr.Key, value)
+}
+
+// toLogLevel translates the slog level to the associated log level
+func toLogLevel(level slog.Level) logLevel {
+	switch {
+	case level >= slog.LevelError:
+		return levelError
+
+	case level >= slog.LevelWarn:
+		return levelWarn
+
+	case level >= slog.LevelInfo:
+		return levelVerbose
+
+	case level >= slog.LevelDebug:
+		return levelDebug
+
+	default:
+		return levelTrace
 	}
 }
 
@@ -53,8 +165,8 @@ func setCurrentProgressIndicator(pi *wait.ProgressIndicator) {
 // translateLogLevel transates the flag boolean to
 // the associated log level.
 // Levels: Fatal < Error < Warn < Verbose < Debug < Trace
-func translateLogLevel() havener.LogLevel {
-	logLevel := havener.Off
+func translateLogLevel() logLevel {
+	logLevel := levelOff
 
 	fatalLevel := viper.GetBool("fatal")
 	errorLevel := viper.GetBool("error")
@@ -63,48 +175,45 @@ func translateLogLevel() havener.LogLevel {
 	debugLevel := viper.GetBool("debug")
 	traceLevel := viper.GetBool("trace")
 
-	if fatalLevel && havener.Fatal > logLevel {
-		logLevel = havener.Fatal
+	if fatalLevel && levelFatal > logLevel {
+		logLevel = levelFatal
 	}
-	if errorLevel && havener.Error > logLevel {
-		logLevel = havener.Error
+	if errorLevel && levelError > logLevel {
+		logLevel = levelError
 	}
-	if warnLevel && havener.Warn > logLevel {
-		logLevel = havener.Warn
+	if warnLevel && levelWarn > logLevel {
+		logLevel = levelWarn
 	}
-	if verboseLevel && havener.Verbose > logLevel {
-		logLevel = havener.Verbose
+	if verboseLevel && levelVerbose > logLevel {
+		logLevel = levelVerbose
 	}
-	if debugLevel && havener.Debug > logLevel {
-		logLevel = havener.Debug
+	if debugLevel && levelDebug > logLevel {
+		logLevel = levelDebug
 	}
-	if traceLevel && havener.Trace > logLevel {
-		logLevel = havener.Trace
+	if traceLevel && levelTrace > logLevel {
+		logLevel = levelTrace
 	}
 
 	return logLevel
 }
 
-// log processes all log messages and logs them differently
-// according to their level
-func log(message havener.LogMessage, targetLevel havener.LogLevel) {
-	if targetLevel >= message.Level {
-		switch message.Level {
-		case havener.Fatal:
-			printLogf("*[FATAL]* %s\n", message.Message)
-		case havener.Error:
-			printLogf("*[ERROR]* %s\n", message.Message)
-		case havener.Warn:
-			printLogf("*[WARN]* %s\n", message.Message)
-		case havener.Verbose:
-			printLogf("*[INFO]* %s\n", message.Message)
-		case havener.Debug:
-			printLogf("*[DEBUG]* %s\n", message.Message)
-		case havener.Trace:
-			printLogf("*[TRACE]* %s\n", message.Message)
-		default:
-			printLogf("*[INFO]* %s\n", message.Message)
-		}
+// log prints the log message differently according to its level
+func log(level logLevel, message string) {
+	switch level {
+	case levelFatal:
+		printLogf("*[FATAL]* %s\n", message)
+	case levelError:
+		printLogf("*[ERROR]* %s\n", message)
+	case levelWarn:
+		printLogf("*[WARN]* %s\n", message)
+	case levelVerbose:
+		printLogf("*[INFO]* %s\n", message)
+	case levelDebug:
+		printLogf("*[DEBUG]* %s\n", message)
+	case levelTrace:
+		printLogf("*[TRACE]* %s\n", message)
+	default:
+		printLogf("*[INFO]* %s\n", message)
 	}
 }
 
@@ -0,0 +1,76 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gonvenience/bunt"
+	. "github.com/homeport/havener/internal/cmd"
+
+	"github.com/homeport/havener/pkg/havener"
+	"github.com/spf13/viper"
+)
+
+var _ = Describe("havener logger", func() {
+	BeforeEach(func() {
+		SetColorSettings(OFF, OFF)
+	})
+
+	AfterEach(func() {
+		SetColorSettings(AUTO, AUTO)
+		viper.Set("verbose", false)
+	})
+
+	It("should not print anything without a log level flag", func() {
+		out := captureStdout(func() {
+			HavenerLogger.Info("Listing pods")
+		})
+
+		Expect(out).To(BeEmpty())
+	})
+
+	It("should print the message with the structured fields", func() {
+		viper.Set("verbose", true)
+
+		out := captureStdout(func() {
+			HavenerLogger.With("cluster", "foobar").WithGroup("exec").Info("Executing command on pod",
+				"pod", "api-0",
+				"command", "echo hello",
+				"filter", havener.ListFilter{Namespaces: []string{"default"}},
+			)
+		})
+
+		Expect(out).To(Equal("[INFO] Executing command on pod cluster=foobar exec.pod=api-0 exec.command=\"echo hello\" exec.filter.namespaces=default\n"))
+	})
+
+	It("should only print messages up to the configured level", func() {
+		viper.Set("verbose", true)
+
+		out := captureStdout(func() {
+			HavenerLogger.Debug("Details")
+			HavenerLogger.Warn("Careful")
+		})
+
+		Expect(out).To(Equal("[WARN] Careful\n"))
+	})
+})
@@ -21,9 +21,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -38,6 +41,10 @@ var (
 	parallelDownloads    int
 	totalDownloadTimeout int
 	downloadLocation     string
+	logsSelector         string
+	logsFieldSelector    string
+	logsProfiles         []string
+	logsProfileFile      string
 )
 
 // logsCmd represents the top command
@@ -49,16 +56,44 @@ files from some well-known hard-coded locations to a local directory. Use this
 to quickly scan through multiple files from multiple locations in case you have
 to debug an issue where it is not clear yet where to look.
 
-The download includes all deployment YAMLs of the pods and the describe output.`,
+The download includes all deployment YAMLs of the pods and the describe output.
+
+The files to download are defined by log profiles. The built-in profile
+'cloudfoundry' covers the locations used by Cloud Foundry workloads, like
+/var/vcap/sys/log. Additional profiles can be defined in a YAML file that is
+passed using --profile-file, for example:
+
+  profiles:
+  - name: postgres
+    namespaces: [database]
+    selector: app=postgres
+    maxSize: 50Mi
+    logs:
+    - path: /var/lib/postgresql/data/log
+      include: ["*.log", "*.csv"]
+    configs:
+    - path: /var/lib/postgresql/data
+      include: ["*.conf"]
+      exclude: ["*.auto.conf"]
+
+Each path is a directory that is searched recursively, or a glob pattern. The
+include and exclude patterns apply to the file names, and files bigger than
+maxSize are skipped. A profile applies to the pods in the listed namespaces
+that match the label selector, both are optional.
+
+For each pod, the first matching profile is used, where the profiles of the
+file come before the built-in profiles, and a profile of the file replaces a
+built-in profile with the same name. Use --profile to only use the selected
+profiles, for example --profile postgres,cloudfoundry.`,
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		hvnr, err := havener.NewHavener(havener.WithContext(cmd.Context()), havener.WithKubeConfigPath(kubeConfig))
+		hvnrs, err := newHaveners(cmd.Context())
 		if err != nil {
 			return fmt.Errorf("unable to get access to cluster: %w", err)
 		}
 
-		return retrieveClusterLogs(hvnr)
+		return retrieveClusterLogs(hvnrs)
 	},
 }
 
@@ -69,9 +104,18 @@ func init() {
 	logsCmd.PersistentFlags().StringVar(&downloadLocation, "target", os.TempDir(), "desired target download location for retrieved files")
 	logsCmd.PersistentFlags().IntVar(&totalDownloadTimeout, "timeout", 5*60, "allowed time in seconds before the download is aborted")
 	logsCmd.PersistentFlags().IntVar(&parallelDownloads, "parallel", 64, "number of parallel download jobs")
+	logsCmd.PersistentFlags().StringVarP(&logsSelector, "selector", "l", "", "label selector of the pods to retrieve files from, for example app=router")
+	logsCmd.PersistentFlags().StringVar(&logsFieldSelector, "field-selector", "", "field selector of the pods to retrieve files from, for example spec.nodeName=node-0")
+	logsCmd.PersistentFlags().StringSliceVar(&logsProfiles, "profile", []string{}, "comma separated list of log profiles to use (default is to use all profiles)")
+	logsCmd.PersistentFlags().StringVar(&logsProfileFile, "profile-file", "", "YAML file with additional log profiles")
 }
 
-func retrieveClusterLogs(hvnr havener.Havener) error {
+func retrieveClusterLogs(hvnrs []havener.Havener) error {
+	profiles, err := selectLogProfiles(logsProfileFile, logsProfiles)
+	if err != nil {
+		return err
+	}
+
 	var commonText string
 	if excludeConfigFiles {
 		commonText = "log files"
@@ -89,11 +133,32 @@ func retrieveClusterLogs(hvnr havener.Havener) error {
 
 	resultChan := make(chan error, 1)
 	go func() {
-		resultChan <- hvnr.RetrieveLogs(
-			parallelDownloads,
-			downloadLocation,
-			!excludeConfigFiles,
+		var (
+			wg   sync.WaitGroup
+			errs = make([]error, len(hvnrs))
 		)
+
+		// Each cluster has its own sub-directory, so all clusters can be
+		// processed at the same time
+		for i, hvnr := range hvnrs {
+			wg.Add(1)
+			go func(i int, hvnr havener.Havener) {
+				defer wg.Done()
+				errs[i] = hvnr.RetrieveLogsWithOptions(havener.RetrieveLogsOptions{
+					ParallelDownloads:  parallelDownloads,
+					Target:             downloadLocation,
+					IncludeConfigFiles: !excludeConfigFiles,
+					Filter: havener.ListFilter{
+						LabelSelector: logsSelector,
+						FieldSelector: logsFieldSelector,
+					},
+					Profiles: profiles,
+				})
+			}(i, hvnr)
+		}
+
+		wg.Wait()
+		resultChan <- errors.Join(errs...)
 	}()
 
 	select {
@@ -117,3 +182,51 @@ func retrieveClusterLogs(hvnr havener.Havener) error {
 
 	return nil
 }
+
+// selectLogProfiles returns the log profiles of the file, followed by the
+// built-in profiles that are not replaced by a profile of the file. In case
+// names are provided, only the profiles with these names are returned.
+func selectLogProfiles(file string, names []string) ([]havener.LogProfile, error) {
+	var available []havener.LogProfile
+	if file != "" {
+		profiles, err := havener.LoadLogProfiles(file)
+		if err != nil {
+			return nil, err
+		}
+
+		available = append(available, profiles...)
+	}
+
+	var lookUp = map[string]havener.LogProfile{}
+	for _, profile := range available {
+		lookUp[profile.Name] = profile
+	}
+
+	for _, profile := range havener.BuiltinLogProfiles() {
+		if _, ok := lookUp[profile.Name]; !ok {
+			lookUp[profile.Name] = profile
+			available = append(available, profile)
+		}
+	}
+
+	if len(names) == 0 {
+		return available, nil
+	}
+
+	var result []havener.LogProfile
+	for _, name := range names {
+		profile, ok := lookUp[name]
+		if !ok {
+			var availableNames []string
+			for _, profile := range available {
+				availableNames = append(availableNames, profile.Name)
+			}
+
+			return nil, fmt.Errorf("unknown log profile %s, available profiles are: %s", name, strings.Join(availableNames, ", "))
+		}
+
+		result = append(result, profile)
+	}
+
+	return result, nil
+}
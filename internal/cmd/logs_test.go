@@ -0,0 +1,83 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/homeport/havener/internal/cmd"
+
+	"github.com/homeport/havener/pkg/havener"
+)
+
+var _ = Describe("logs", func() {
+	Context("selecting log profiles", func() {
+		var names = func(profiles []havener.LogProfile) []string {
+			var result []string
+			for _, profile := range profiles {
+				result = append(result, profile.Name)
+			}
+
+			return result
+		}
+
+		var profileFile = func() string {
+			path := filepath.Join(GinkgoT().TempDir(), "profiles.yml")
+			Expect(os.WriteFile(path, []byte(`profiles:
+- name: java
+  selector: app=api
+  logs:
+  - path: /opt/app/logs
+- name: cloudfoundry
+  namespaces: [cf]
+  logs:
+  - path: /var/vcap/sys/log
+`), 0644)).To(Succeed())
+
+			return path
+		}
+
+		It("should use the built-in profiles by default", func() {
+			profiles, err := SelectLogProfiles("", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(profiles).To(Equal(havener.BuiltinLogProfiles()))
+		})
+
+		It("should use the profiles of the file before the built-in profiles, which they can replace", func() {
+			profiles, err := SelectLogProfiles(profileFile(), nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names(profiles)).To(Equal([]string{"java", "cloudfoundry"}))
+			Expect(profiles[1].Namespaces).To(Equal([]string{"cf"}))
+		})
+
+		It("should only use the selected profiles", func() {
+			profiles, err := SelectLogProfiles(profileFile(), []string{"cloudfoundry"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(names(profiles)).To(Equal([]string{"cloudfoundry"}))
+
+			_, err = SelectLogProfiles("", []string{"java"})
+			Expect(err).To(MatchError("unknown log profile java, available profiles are: cloudfoundry"))
+		})
+	})
+})
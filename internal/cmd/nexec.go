@@ -22,6 +22,7 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -32,6 +33,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/gonvenience/bunt"
@@ -48,13 +50,26 @@ const (
 )
 
 var nodeExecCmdSettings struct {
-	stdin        bool
-	tty          bool
-	notty        bool
-	image        string
-	maxParallel  int
-	timeout      time.Duration
-	printAsBlock bool
+	stdin         bool
+	tty           bool
+	notty         bool
+	image         string
+	maxParallel   int
+	timeout       time.Duration
+	printAsBlock  bool
+	group         bool
+	output        string
+	outputDir     string
+	quiet         bool
+	template      bool
+	script        string
+	diff          bool
+	selector      string
+	fieldSelector string
+	env           []string
+	workDir       string
+	execTimeout   time.Duration
+	stdinLimit    string
 }
 
 // nodeExecCmd represents the node-exec command
@@ -73,10 +88,20 @@ example _havener node-exec foo_ will search for a node named 'foo' and open a
 shell if the node can be found.
 
 When more than one node is specified, it will execute the command on all nodes.
-In this distributed mode, both passing the StdIn as well as TTY mode are not
-available. By default, the number of parallel node executions is limited to %d
-in parallel in order to not create to many requests at the same time. This
-value can be overwritten. Handle with care.
+In this distributed mode, TTY mode is not available and the StdIn is read
+completely before it is passed to each node, for example to pipe a script
+into a shell on all nodes. The size of the StdIn is limited, which can be
+changed using _--stdin-limit_. By default, the number of parallel node
+executions is limited to %d in parallel in order to not create to many
+requests at the same time. This value can be overwritten. Handle with care.
+
+With _--group_, nodes with identical output are combined, so that each
+distinct output is only shown once. Use _--diff_ to show outputs that differ
+from the majority output as a diff against it.
+
+With one node, *havener* exits with the exit code of the command. With more
+than one node, a summary of the exit codes is shown at the end and *havener*
+fails if the command failed on at least one of the nodes.
 
 If you run the _node-exec_ without any additional arguments, it will print a
 list of available nodes in the cluster.
@@ -84,6 +109,35 @@ list of available nodes in the cluster.
 For convenience, if the target node name _all_ is used, *havener* will look up
 all nodes automatically.
 
+With a label selector (_--selector_) or field selector (_--field-selector_),
+all matching nodes are used as the target. In this case, the node argument is
+omitted and all arguments are used as the command, for example
+_havener node-exec -l node-role.kubernetes.io/worker -- uptime_.
+
+Environment variables (_--env_) and a working directory (_--workdir_) are set
+by running the command through _/bin/sh_ on the node. The _--exec-timeout_
+limits the duration of the command itself on each node, while _--timeout_
+limits the setup of the helper pod.
+
+With _--template_, each command argument is a Go template, which is expanded
+for each node right before the execution. The node (_.Node_) and the cluster
+name (_.Cluster_) are available, for example
+_havener node-exec --template all -- echo '{{index .Node.Labels "zone"}}'_.
+
+With _--script_, the local script is uploaded into a temporary directory on
+each node, run there, and removed afterwards. All arguments after the node are
+passed to the script, for example _havener node-exec --script ./diag.sh all_.
+
+With _--output jsonl_, each output line is printed as a JSON object including
+the timestamp, stream, and node, followed by one object per node with the exit
+code and error, so that the results can be processed by scripts. This output
+format is also used for a single node.
+
+With _--output-dir_, the standard output and error of each node are written
+into the files _<node>.stdout_ and _<node>.stderr_ in the given directory,
+together with a _summary.json_ file with the exit codes and durations. Use
+_--quiet_ to not show the command output in the terminal.
+
 `, nodeExecDefaultCommand, nodeExecDefaultMaxParallel),
 	SilenceUsage:  true,
 	SilenceErrors: true,
@@ -99,12 +153,16 @@ all nodes automatically.
 			nodeExecCmdSettings.tty = !nodeExecCmdSettings.notty
 		}
 
-		hvnr, err := havener.NewHavener(havener.WithContext(cmd.Context()), havener.WithKubeConfigPath(kubeConfig))
+		if err := validateOutputFormat(nodeExecCmdSettings.output); err != nil {
+			return err
+		}
+
+		hvnrs, err := newHaveners(cmd.Context())
 		if err != nil {
 			return fmt.Errorf("unable to get access to cluster: %w", err)
 		}
 
-		return execInClusterNodes(hvnr, args)
+		return execInClusterNodes(hvnrs, args)
 	},
 }
 
@@ -114,41 +172,98 @@ func init() {
 	nodeExecCmd.Flags().SortFlags = false
 	nodeExecCmd.Flags().BoolVarP(&nodeExecCmdSettings.stdin, "stdin", "i", false, "Pass stdin to the container")
 	nodeExecCmd.Flags().BoolVarP(&nodeExecCmdSettings.tty, "tty", "t", false, "Stdin is a TTY")
+	nodeExecCmd.Flags().StringVar(&nodeExecCmdSettings.stdinLimit, "stdin-limit", stdinDefaultLimit, "Maximum size of stdin that is passed to more than one node")
 	nodeExecCmd.Flags().StringVar(&nodeExecCmdSettings.image, "image", nodeExecDefaultImage, "Container image used for helper pod (from which the root-shell is accessed)")
 	nodeExecCmd.Flags().DurationVar(&nodeExecCmdSettings.timeout, "timeout", nodeExecDefaultTimeout, "Timeout for the setup of the helper pod")
 	nodeExecCmd.Flags().IntVar(&nodeExecCmdSettings.maxParallel, "max-parallel", nodeExecDefaultMaxParallel, "Number of parallel executions (value less or equal than zero means unlimited)")
 	nodeExecCmd.Flags().BoolVar(&nodeExecCmdSettings.printAsBlock, "block", false, "Show distributed shell output as block for each node")
+	nodeExecCmd.Flags().BoolVar(&nodeExecCmdSettings.group, "group", false, "Show distributed shell output once for all nodes with identical output")
+	nodeExecCmd.Flags().BoolVar(&nodeExecCmdSettings.diff, "diff", false, "Show differing output as a diff against the majority output (implies --group)")
+	nodeExecCmd.Flags().StringVarP(&nodeExecCmdSettings.output, "output", "o", outputFormatText, "Output format of the distributed shell output, which is text or jsonl")
+	nodeExecCmd.Flags().StringVar(&nodeExecCmdSettings.outputDir, "output-dir", "", "Write the output of each node into files in the given directory, including a summary.json")
+	nodeExecCmd.Flags().BoolVarP(&nodeExecCmdSettings.quiet, "quiet", "q", false, "Do not show the command output in the terminal")
+	nodeExecCmd.Flags().StringVarP(&nodeExecCmdSettings.selector, "selector", "l", "", "Label selector of the target nodes, for example kubernetes.io/arch=arm64")
+	nodeExecCmd.Flags().StringVar(&nodeExecCmdSettings.fieldSelector, "field-selector", "", "Field selector of the target nodes, for example metadata.name=node-0")
+	nodeExecCmd.Flags().StringArrayVar(&nodeExecCmdSettings.env, "env", nil, "Environment variable in KEY=VALUE format for the command (can be used multiple times)")
+	nodeExecCmd.Flags().StringVar(&nodeExecCmdSettings.workDir, "workdir", "", "Working directory on the node for the command")
+	nodeExecCmd.Flags().DurationVar(&nodeExecCmdSettings.execTimeout, "exec-timeout", 0, "Maximum duration of the command execution on each node, zero means no limit (see --timeout for the helper pod setup)")
+	nodeExecCmd.Flags().BoolVar(&nodeExecCmdSettings.template, "template", false, "Expand the command arguments as Go templates for each node, for example {{.Node.Name}}")
+	nodeExecCmd.Flags().StringVar(&nodeExecCmdSettings.script, "script", "", "Upload the local script to each node and run it, remaining arguments are passed to the script")
 
 	// Deprecated/old flags
 	nodeExecCmd.Flags().BoolVar(&nodeExecCmdSettings.notty, "no-tty", false, "do not allocate pseudo-terminal for command execution")
 	_ = nodeExecCmd.Flags().MarkDeprecated("no-tty", "use --tty flag instead")
 }
 
-func execInClusterNodes(hvnr havener.Havener, args []string) error {
+func execInClusterNodes(hvnrs []havener.Havener, args []string) error {
+	type task struct {
+		hvnr havener.Havener
+		node corev1.Node
+	}
+
 	var (
-		nodes   []corev1.Node
+		tasks   []task
 		input   string
 		command []string
-		err     error
 	)
 
+	var filter = havener.ListFilter{
+		LabelSelector: nodeExecCmdSettings.selector,
+		FieldSelector: nodeExecCmdSettings.fieldSelector,
+	}
+
+	var selected = filter.LabelSelector != "" || filter.FieldSelector != ""
+
+	// With a script, all arguments are passed to the script, so there is no
+	// default command
+	var defaultCommand = []string{nodeExecDefaultCommand}
+	if nodeExecCmdSettings.script != "" {
+		defaultCommand = nil
+	}
+
 	switch {
+	case selected && len(args) > 0: // nodes are selected, only command is given
+		input, command = "all", args
+
+	case selected: // nodes are selected, no command is given
+		input, command = "all", defaultCommand
+
 	case len(args) >= 2: // node name and command is given
 		input, command = args[0], args[1:]
-		nodes, err = lookupNodesByName(hvnr, input)
-		if err != nil {
+
+	case len(args) == 1: // only node name is given
+		input, command = args[0], defaultCommand
+
+	default: // no arguments
+		return availableNodesError(hvnrs, nil, "no node name and command specified")
+	}
+
+	// The script is uploaded using the standard input of the command
+	var upload *script
+	if nodeExecCmdSettings.script != "" {
+		if nodeExecCmdSettings.stdin {
+			return fmt.Errorf("cannot use --stdin and --script at the same time")
+		}
+
+		var err error
+		if upload, err = loadScript(nodeExecCmdSettings.script); err != nil {
 			return err
 		}
+	}
 
-	case len(args) == 1: // only node name is given
-		input, command = args[0], []string{nodeExecDefaultCommand}
-		nodes, err = lookupNodesByName(hvnr, input)
+	for _, hvnr := range hvnrs {
+		nodes, err := lookupNodesByName(hvnr, input, filter)
 		if err != nil {
 			return err
 		}
 
-	default: // no arguments
-		return availableNodesError(hvnr, "no node name and command specified")
+		for i := range nodes {
+			tasks = append(tasks, task{hvnr: hvnr, node: nodes[i]})
+		}
+	}
+
+	if selected && len(tasks) == 0 {
+		return availableNodesError(hvnrs, nil, "no node matches the selector")
 	}
 
 	if !isStdinTerminal() {
@@ -160,19 +275,52 @@ func execInClusterNodes(hvnr havener.Havener, args []string) error {
 		in = os.Stdin
 	}
 
-	var nodeExecHelperPodConfig = havener.NodeExecHelperPodConfig{
-		Annotations:    map[string]string{},
-		ContainerImage: nodeExecCmdSettings.image,
-		ContainerCmd:   []string{"/bin/sleep", "8h"},
-		WaitTimeout:    nodeExecCmdSettings.timeout,
+	if upload != nil {
+		nodeExecCmdSettings.tty = false
+		in = upload.stdin()
 	}
 
-	nodeExecHelperPodConfig.Annotations["originator"] = originator()
+	// With templates enabled, the command is expanded for each node right
+	// before the execution
+	var commandFor = func(task) ([]string, error) { return command, nil }
+	if nodeExecCmdSettings.template {
+		tmpl, err := newCommandTemplate(command)
+		if err != nil {
+			return err
+		}
+
+		commandFor = func(t task) ([]string, error) {
+			return tmpl.expand(commandTemplateData{
+				Cluster: t.hvnr.ClusterName(),
+				Node:    &t.node,
+			})
+		}
+	}
 
-	// Single node mode, use default streams and run node execute function
-	if len(nodes) == 1 {
-		return hvnr.NodeExec(
-			nodes[0],
+	var nodeExecHelperPodConfig = newNodeExecHelperPodConfig(nodeExecCmdSettings.image, nodeExecCmdSettings.timeout)
+
+	if upload != nil {
+		var argsFor = commandFor
+		commandFor = func(t task) ([]string, error) {
+			args, err := argsFor(t)
+			if err != nil {
+				return nil, err
+			}
+
+			return upload.command(args), nil
+		}
+	}
+
+	// Single node mode, use default streams and run node execute function, unless
+	// the output is meant to be processed or stored
+	if len(tasks) == 1 && nodeExecCmdSettings.output != outputFormatJSONL && nodeExecCmdSettings.outputDir == "" && !nodeExecCmdSettings.quiet {
+		command, err := commandFor(tasks[0])
+		if err != nil {
+			return err
+		}
+
+		result, err := tasks[0].hvnr.NodeExecWithResult(
+			tasks[0].node,
 			nodeExecHelperPodConfig,
 			havener.ExecConfig{
 				Command: command,
@@ -180,64 +328,124 @@ func execInClusterNodes(hvnr havener.Havener, args []string) error {
 				Stdout:  os.Stdout,
 				Stderr:  os.Stderr,
 				TTY:     nodeExecCmdSettings.tty,
+				Env:     nodeExecCmdSettings.env,
+				WorkDir: nodeExecCmdSettings.workDir,
+				Timeout: nodeExecCmdSettings.execTimeout,
 			},
 		)
+
+		if err != nil {
+			return err
+		}
+
+		return exitWithCode(result.ExitCode)
 	}
 
-	// In distributed shell mode, stdin is not piped through and TTY is forced to be disabled
-	nodeExecCmdSettings.stdin = false
+	// In distributed shell mode, TTY is forced to be disabled and the standard
+	// input is read once to pass the same input to each node
 	nodeExecCmdSettings.tty = false
 
-	// In case the user wants everything done in parallel, increase the max value
-	if nodeExecCmdSettings.maxParallel <= 0 {
-		nodeExecCmdSettings.maxParallel = len(nodes)
+	var stdin = func() io.Reader { return nil }
+	if nodeExecCmdSettings.stdin {
+		input, err := replayableInput(os.Stdin, nodeExecCmdSettings.stdinLimit)
+		if err != nil {
+			return err
+		}
+
+		stdin = input
 	}
 
-	type task struct {
-		node corev1.Node
+	if upload != nil {
+		stdin = upload.stdin
+	}
+
+	// In case the user wants everything done in parallel, increase the max value
+	if nodeExecCmdSettings.maxParallel <= 0 {
+		nodeExecCmdSettings.maxParallel = len(tasks)
 	}
 
 	var (
-		wg      = &sync.WaitGroup{}
-		tasks   = make(chan task, len(nodes))
-		output  = make(chan OutputMsg)
-		errors  = make(chan error, len(nodes))
-		printer = make(chan bool, 1)
+		wg           = &sync.WaitGroup{}
+		queue        = make(chan int, len(tasks))
+		output       = make(chan OutputMsg)
+		errors       = make(chan error, len(tasks))
+		printer      = make(chan bool, 1)
+		multiCluster = len(hvnrs) > 1
+		summaries    = make([]execSummary, len(tasks))
 	)
 
 	// Fill task queue with the list of nodes to be processed
-	for i := range nodes {
-		tasks <- task{node: nodes[i]}
+	for i := range tasks {
+		queue <- i
 	}
-	close(tasks)
+	close(queue)
 
 	// Start n task workers to work on task queue
 	wg.Add(nodeExecCmdSettings.maxParallel)
 	for i := 0; i < nodeExecCmdSettings.maxParallel; i++ {
 		go func() {
 			defer wg.Done()
-			for task := range tasks {
-				errors <- hvnr.NodeExec(
+			for i := range queue {
+				task := tasks[i]
+				cluster := clusterOf(task.hvnr, multiCluster)
+				target := OutputTarget{Node: task.node.Name}
+				command, err := commandFor(task)
+				if err != nil {
+					summaries[i] = execSummary{cluster: cluster, target: target, err: err}
+					errors <- err
+					continue
+				}
+
+				stdout, stderr, err := targetWriters(cluster, target, output, nodeExecCmdSettings.outputDir, nodeExecCmdSettings.quiet)
+				if err != nil {
+					summaries[i] = execSummary{cluster: cluster, target: target, err: err}
+					errors <- err
+					continue
+				}
+
+				result, err := task.hvnr.NodeExecWithResult(
 					task.node,
 					nodeExecHelperPodConfig,
 					havener.ExecConfig{
 						Command: command,
-						Stdin:   nil, // Disabled for now until reliable input duplication works
-						Stdout:  chanWriter("StdOut", task.node.Name, output),
-						Stderr:  chanWriter("StdErr", task.node.Name, output),
+						Stdin:   stdin(),
+						Stdout:  stdout,
+						Stderr:  stderr,
 						TTY:     nodeExecCmdSettings.tty,
+						Env:     nodeExecCmdSettings.env,
+						WorkDir: nodeExecCmdSettings.workDir,
+						Timeout: nodeExecCmdSettings.execTimeout,
 					},
 				)
+
+				_ = stdout.Close()
+				_ = stderr.Close()
+
+				summaries[i] = execSummary{cluster: cluster, target: target, result: result, err: err}
+				errors <- err
 			}
 		}()
 	}
 
 	// Start the respective output printer in a separate Go routine
+	var printErr error
 	go func() {
-		if nodeExecCmdSettings.printAsBlock {
+		switch {
+		case nodeExecCmdSettings.output == outputFormatJSONL:
+			printErr = PrintOutputMessageJSON(output)
+
+		case nodeExecCmdSettings.group || nodeExecCmdSettings.diff:
+			var sources = make([]string, len(tasks))
+			for i, task := range tasks {
+				sources[i] = outputSource(clusterOf(task.hvnr, multiCluster), OutputTarget{Node: task.node.Name}.Name())
+			}
+
+			PrintOutputMessageGrouped(output, sources, "node", nodeExecCmdSettings.diff)
+
+		case nodeExecCmdSettings.printAsBlock:
 			PrintOutputMessageAsBlock(output)
 
-		} else {
+		default:
 			PrintOutputMessage(output)
 		}
 
@@ -247,9 +455,46 @@ func execInClusterNodes(hvnr havener.Havener, args []string) error {
 	wg.Wait()
 	close(errors)
 	close(output)
-
 	<-printer
-	return combineErrorsFromChannel("node command execution failed", errors)
+
+	if nodeExecCmdSettings.outputDir != "" {
+		if err := writeSummaryFile(nodeExecCmdSettings.outputDir, summaries); err != nil {
+			return err
+		}
+	}
+
+	switch nodeExecCmdSettings.output {
+	case outputFormatJSONL:
+		if printErr != nil {
+			return printErr
+		}
+
+		if err := printExecResultsJSON(summaries); err != nil {
+			return err
+		}
+
+	default:
+		if err := printExecSummary(summaries); err != nil {
+			return err
+		}
+	}
+
+	if err := combineErrorsFromChannel("node command execution failed", errors); err != nil {
+		return err
+	}
+
+	return exitWithCode(summaryExitCode(summaries))
+}
+
+// newNodeExecHelperPodConfig returns the configuration of the helper pod that
+// is used to access a node
+func newNodeExecHelperPodConfig(image string, timeout time.Duration) havener.NodeExecHelperPodConfig {
+	return havener.NodeExecHelperPodConfig{
+		Annotations:    map[string]string{"originator": originator()},
+		ContainerImage: image,
+		ContainerCmd:   []string{"/bin/sleep", "8h"},
+		WaitTimeout:    timeout,
+	}
 }
 
 func originator() string {
@@ -274,16 +519,23 @@ func originator() string {
 	)
 }
 
-func lookupNodesByName(h havener.Havener, input string) ([]corev1.Node, error) {
+func lookupNodesByName(h havener.Havener, input string, filter havener.ListFilter) ([]corev1.Node, error) {
 	if input == "all" {
-		return h.ListNodes()
+		return h.ListNodesWithOptions(filter)
 	}
 
 	var nodeList []corev1.Node
 	for _, nodeName := range strings.Split(input, ",") {
 		node, err := h.Client().CoreV1().Nodes().Get(h.Context(), nodeName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil, availableNodesError([]havener.Havener{h},
+				&havener.ResourceNotFoundError{Kind: havener.KindNode, Name: nodeName},
+				"node '%s' does not exist in cluster %s", nodeName, h.ClusterName(),
+			)
+		}
+
 		if err != nil {
-			return nil, availableNodesError(h, "node '%s' does not exist", nodeName)
+			return nil, fmt.Errorf("failed to get node '%s' in cluster %s: %w", nodeName, h.ClusterName(), err)
 		}
 
 		nodeList = append(nodeList, *node)
@@ -292,16 +544,10 @@ func lookupNodesByName(h havener.Havener, input string) ([]corev1.Node, error) {
 	return nodeList, nil
 }
 
-func availableNodesError(h havener.Havener, title string, fArgs ...interface{}) error {
-	nodes, err := h.ListNodes()
-	if err != nil {
-		return fmt.Errorf("failed to list all nodes in cluster: %w", err)
-	}
-
-	if len(nodes) == 0 {
-		return fmt.Errorf("failed to find any node in cluster")
-	}
-
+// availableNodesError returns an error with the list of available nodes, the
+// cause (if set) is part of the error chain, for example to describe that a
+// node does not exist
+func availableNodesError(hvnrs []havener.Havener, cause error, title string, fArgs ...interface{}) error {
 	var buf bytes.Buffer
 	t := tablewriter.NewWriter(&buf)
 	t.SetBorder(false)
@@ -309,26 +555,47 @@ func availableNodesError(h havener.Havener, title string, fArgs ...interface{})
 	// t.SetAutoWrapText(false)
 	t.SetAlignment(tablewriter.ALIGN_LEFT)
 
-	for _, node := range nodes {
-		var tmp []string
-		for _, condition := range node.Status.Conditions {
-			if condition.Status == corev1.ConditionTrue {
-				tmp = append(tmp, string(condition.Type))
+	var count int
+	for _, h := range hvnrs {
+		nodes, err := h.ListNodes()
+		if err != nil {
+			return fmt.Errorf("failed to list all nodes in cluster: %w", err)
+		}
+
+		for _, node := range nodes {
+			var tmp []string
+			for _, condition := range node.Status.Conditions {
+				if condition.Status == corev1.ConditionTrue {
+					tmp = append(tmp, string(condition.Type))
+				}
+			}
+
+			var row []string
+			if len(hvnrs) > 1 {
+				row = append(row, h.ClusterName())
 			}
+
+			t.Append(append(row,
+				node.Name,
+				strings.Join(tmp, ", "),
+				fmt.Sprintf("%s/%s", node.Status.NodeInfo.OperatingSystem, node.Status.NodeInfo.Architecture),
+				node.Status.NodeInfo.KubeletVersion,
+			))
+
+			count++
 		}
+	}
 
-		t.Append([]string{
-			node.Name,
-			strings.Join(tmp, ", "),
-			fmt.Sprintf("%s/%s", node.Status.NodeInfo.OperatingSystem, node.Status.NodeInfo.Architecture),
-			node.Status.NodeInfo.KubeletVersion,
-		})
+	if count == 0 {
+		return fmt.Errorf("failed to find any node in cluster")
 	}
 
 	t.Render()
 
-	return fmt.Errorf("%s: %w",
-		fmt.Sprintf(title, fArgs...),
-		bunt.Errorf("List of available nodes:\n%s\nAlternatively, use _all_ to target all nodes.", buf.String()),
-	)
+	var details = bunt.Sprintf("List of available nodes:\n%s\nAlternatively, use _all_ to target all nodes.", buf.String())
+	if cause == nil {
+		return fmt.Errorf("%s: %w", fmt.Sprintf(title, fArgs...), errors.New(details))
+	}
+
+	return fmt.Errorf("%s: %w", fmt.Sprintf(title, fArgs...), fmt.Errorf("%w\n\n%s", cause, details))
 }
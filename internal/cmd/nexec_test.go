@@ -0,0 +1,163 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gonvenience/bunt"
+	. "github.com/homeport/havener/internal/cmd"
+
+	"github.com/homeport/havener/pkg/havener"
+	"github.com/homeport/havener/pkg/havener/havenertest"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("node-exec", func() {
+	var hvnr *havenertest.Havener
+
+	BeforeEach(func() {
+		SetColorSettings(OFF, OFF)
+
+		hvnr = havenertest.NewHavener(
+			exampleNode("node-1"),
+			exampleNode("node-2"),
+		)
+	})
+
+	AfterEach(func() {
+		SetColorSettings(AUTO, AUTO)
+	})
+
+	It("should execute the command on all nodes", func() {
+		hvnr.Exec = havenertest.Reply("up 42 days\n", "", 0)
+
+		out := captureStdout(func() {
+			Expect(ExecInClusterNodes([]havener.Havener{hvnr}, []string{"all", "uptime"})).To(Succeed())
+		})
+
+		Expect(hvnr.Executions()).To(ConsistOf("node-1", "node-2"))
+		Expect(out).To(ContainSubstring("node-1 │ up 42 days\n"))
+		Expect(out).To(ContainSubstring("node-2 │ up 42 days\n"))
+	})
+
+	It("should print a summary of the exit codes and fail if the command failed on one node", func() {
+		hvnr.Exec = func(target havenertest.ExecTarget, execConfig havener.ExecConfig) error {
+			if target.Node.Name == "node-2" {
+				return havenertest.Reply("", "no such file\n", 2)(target, execConfig)
+			}
+
+			return havenertest.Reply("ok\n", "", 0)(target, execConfig)
+		}
+
+		var err error
+		out := captureStdout(func() {
+			err = ExecInClusterNodes([]havener.Havener{hvnr}, []string{"all", "cat", "/foo"})
+		})
+
+		Expect(ExitCodeOf(err)).To(Equal(1))
+		Expect(out).To(ContainSubstring("Summary"))
+		Expect(out).To(MatchRegexp(`node-1\s+0\s`))
+		Expect(out).To(MatchRegexp(`node-2\s+2\s`))
+	})
+
+	It("should exit with the exit code of the command on a single node", func() {
+		hvnr.Exec = havenertest.Reply("", "", 3)
+
+		err := ExecInClusterNodes([]havener.Havener{hvnr}, []string{"node-1", "false"})
+		Expect(ExitCodeOf(err)).To(Equal(3))
+	})
+
+	It("should print JSON lines with one result record per node", func() {
+		DeferCleanup(SetExecOutput("jsonl"))
+		hvnr.Exec = havenertest.Reply("up 42 days\n", "", 0)
+
+		out := captureStdout(func() {
+			Expect(ExecInClusterNodes([]havener.Havener{hvnr}, []string{"all", "uptime"})).To(Succeed())
+		})
+
+		Expect(out).To(ContainSubstring(`"type":"output"`))
+		Expect(out).To(ContainSubstring(`"node":"node-1","stream":"stdout","message":"up 42 days"`))
+		Expect(out).To(ContainSubstring(`{"type":"result","node":"node-1","exitCode":0,`))
+		Expect(out).To(ContainSubstring(`{"type":"result","node":"node-2","exitCode":0,`))
+		Expect(out).ToNot(ContainSubstring("Summary"))
+	})
+
+	It("should write the output into the output directory even for a single node", func() {
+		dir := GinkgoT().TempDir()
+		DeferCleanup(SetExecOutputDir(dir, false))
+		hvnr.Exec = havenertest.Reply("up 42 days\n", "", 0)
+
+		out := captureStdout(func() {
+			Expect(ExecInClusterNodes([]havener.Havener{hvnr}, []string{"node-1", "uptime"})).To(Succeed())
+		})
+
+		Expect(out).To(ContainSubstring("node-1 │ up 42 days\n"))
+		Expect(os.ReadFile(filepath.Join(dir, "node-1.stdout"))).To(BeEquivalentTo("up 42 days\n"))
+		Expect(os.ReadFile(filepath.Join(dir, "node-1.stderr"))).To(BeEmpty())
+		Expect(filepath.Join(dir, "summary.json")).To(BeARegularFile())
+	})
+
+	It("should expand the command template for each node", func() {
+		DeferCleanup(SetExecTemplate(true))
+		hvnr.Exec = func(target havenertest.ExecTarget, execConfig havener.ExecConfig) error {
+			Expect(execConfig.Command).To(Equal([]string{"echo", target.Node.Name + "@fake"}))
+			return nil
+		}
+
+		captureStdout(func() {
+			Expect(ExecInClusterNodes([]havener.Havener{hvnr}, []string{"all", "echo", "{{.Node.Name}}@{{.Cluster}}"})).To(Succeed())
+		})
+
+		Expect(hvnr.Executions()).To(HaveLen(2))
+	})
+
+	It("should fail with the list of available nodes for an unknown node", func() {
+		err := ExecInClusterNodes([]havener.Havener{hvnr}, []string{"node-3", "uptime"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("node-1"))
+		Expect(err.Error()).To(ContainSubstring("node-2"))
+
+		var notFoundErr *havener.ResourceNotFoundError
+		Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+		Expect(notFoundErr.Kind).To(Equal(havener.KindNode))
+		Expect(notFoundErr.Name).To(Equal("node-3"))
+	})
+
+	It("should not report other errors of the node look-up as a missing node", func() {
+		hvnr.Clientset.PrependReactor("get", "nodes", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "nodes"}, "node-1", errors.New("no access"))
+		})
+
+		err := ExecInClusterNodes([]havener.Havener{hvnr}, []string{"node-1", "uptime"})
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(err).ToNot(MatchError(havener.ErrResourceNotFound))
+	})
+})
@@ -0,0 +1,131 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// summaryFileName is the name of the file in the output directory, which
+// contains the command execution result of each target
+const summaryFileName = "summary.json"
+
+// multiWriteCloser writes to all of its writers and closes all of them
+type multiWriteCloser []io.WriteCloser
+
+func (m multiWriteCloser) Write(p []byte) (int, error) {
+	for _, w := range m {
+		if n, err := w.Write(p); err != nil {
+			return n, err
+		}
+	}
+
+	return len(p), nil
+}
+
+func (m multiWriteCloser) Close() error {
+	var errs []error
+	for _, w := range m {
+		errs = append(errs, w.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// targetWriters returns the writers for the standard output and error of the
+// command execution on a target. Unless quiet is set, the output is sent to
+// the output message channel. In case an output directory is set, the output
+// is also written into files of the target in the directory.
+func targetWriters(cluster string, target OutputTarget, c chan OutputMsg, dir string, quiet bool) (io.WriteCloser, io.WriteCloser, error) {
+	var stdout, stderr multiWriteCloser
+	if !quiet {
+		stdout = append(stdout, chanWriter("StdOut", cluster, target, c))
+		stderr = append(stderr, chanWriter("StdErr", cluster, target, c))
+	}
+
+	if dir != "" {
+		var base = outputFilePath(dir, cluster, target)
+		if err := os.MkdirAll(filepath.Dir(base), os.FileMode(0755)); err != nil {
+			return nil, nil, errors.Join(fmt.Errorf("failed to create output directory: %w", err), stdout.Close(), stderr.Close())
+		}
+
+		for _, entry := range []struct {
+			writers *multiWriteCloser
+			suffix  string
+		}{
+			{&stdout, ".stdout"},
+			{&stderr, ".stderr"},
+		} {
+			file, err := os.Create(base + entry.suffix)
+			if err != nil {
+				return nil, nil, errors.Join(fmt.Errorf("failed to create output file: %w", err), stdout.Close(), stderr.Close())
+			}
+
+			*entry.writers = append(*entry.writers, file)
+		}
+	}
+
+	return stdout, stderr, nil
+}
+
+// outputFilePath returns the path of the output files of a target without the
+// file extension, which is <namespace>/<pod>/<container> for pod containers,
+// or <node> for nodes, prefixed with the cluster name if it is set
+func outputFilePath(dir string, cluster string, target OutputTarget) string {
+	var elements = []string{dir}
+	if cluster != "" {
+		elements = append(elements, cluster)
+	}
+
+	switch {
+	case target.Node != "":
+		elements = append(elements, target.Node)
+
+	default:
+		elements = append(elements, target.Namespace, target.Pod, target.Container)
+	}
+
+	return filepath.Join(elements...)
+}
+
+// writeSummaryFile writes the command execution result of each target into
+// the summary file in the output directory
+func writeSummaryFile(dir string, summaries []execSummary) error {
+	if err := os.MkdirAll(dir, os.FileMode(0755)); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(execResultRecords(summaries), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, summaryFileName), append(data, '\n'), os.FileMode(0644)); err != nil {
+		return fmt.Errorf("failed to write summary file: %w", err)
+	}
+
+	return nil
+}
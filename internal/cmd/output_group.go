@@ -0,0 +1,385 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gonvenience/bunt"
+	"github.com/gonvenience/neat"
+)
+
+// diffContextLines is the number of unchanged lines shown around a change
+const diffContextLines = 3
+
+// diffMaxCells limits the size of the table used to compare two outputs, in
+// case the outputs are too large, all lines are shown as changed
+const diffMaxCells = 4 * 1024 * 1024
+
+var numberedName = regexp.MustCompile(`^(.*?)(\d+)(\D*)$`)
+
+// outputGroup is a set of origins that produced identical output
+type outputGroup struct {
+	sources  []string
+	messages []OutputMsg
+}
+
+// PrintOutputMessageGrouped reads from the given output message channel and
+// buffers the input until the channel is closed. Once closed, origins with
+// identical output are merged and each distinct output is printed only once
+// with a headline that lists the respective origins, for which the provided
+// noun is used. The provided sources are all origins the output can come
+// from, so that the ones without any output are shown as a group of their
+// own. With diff enabled, outputs that differ from the majority output are
+// shown as a unified diff against it.
+func PrintOutputMessageGrouped(messages chan OutputMsg, sources []string, noun string, diff bool) {
+	var data = map[string][]OutputMsg{}
+	for _, source := range sources {
+		data[source] = nil
+	}
+
+	for msg := range messages {
+		data[msg.source()] = append(data[msg.source()], msg)
+	}
+
+	sources = make([]string, 0, len(data))
+	for source := range data {
+		sources = append(sources, source)
+	}
+
+	sort.Strings(sources)
+
+	var (
+		groups []*outputGroup
+		lookup = map[[sha256.Size]byte]*outputGroup{}
+	)
+
+	for _, source := range sources {
+		hash := outputHash(data[source])
+		group, ok := lookup[hash]
+		if !ok {
+			group = &outputGroup{messages: data[source]}
+			lookup[hash] = group
+			groups = append(groups, group)
+		}
+
+		group.sources = append(group.sources, source)
+	}
+
+	// The largest group, which is the majority output, comes first
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].sources) > len(groups[j].sources)
+	})
+
+	for i, group := range groups {
+		var headline = fmt.Sprintf("%s (%d %s)",
+			compactNames(group.sources),
+			len(group.sources),
+			pluralize(noun, len(group.sources)),
+		)
+
+		var content string
+		switch {
+		case len(group.messages) == 0:
+			content = bunt.Sprintf("Gray{(no output)}")
+
+		case diff && i > 0 && len(groups[0].messages) > 0:
+			headline += ", difference to the majority output"
+			content = unifiedDiff(outputLines(groups[0].messages), outputLines(group.messages))
+
+		default:
+			var lines = make([]string, len(group.messages))
+			for j, msg := range group.messages {
+				lines[j] = styledMessage(msg)
+			}
+
+			content = strings.Join(lines, "\n")
+		}
+
+		if i > 0 {
+			fmt.Println()
+		}
+
+		fmt.Println(neat.ContentBox(
+			headline,
+			content,
+			neat.HeadlineColor(bunt.SkyBlue),
+			neat.NoFinalEndOfLine(),
+			neat.NoLineWrap(),
+		))
+	}
+}
+
+// outputHash returns a hash of the streams and messages of an output
+func outputHash(messages []OutputMsg) [sha256.Size]byte {
+	var hash = sha256.New()
+	for _, msg := range messages {
+		_, _ = fmt.Fprintf(hash, "%s\x00%s\n", msg.Stream, msg.Message)
+	}
+
+	var result [sha256.Size]byte
+	copy(result[:], hash.Sum(nil))
+	return result
+}
+
+// outputLines returns the lines of an output, where error stream lines are
+// marked to keep them apart from the same text in the standard output
+func outputLines(messages []OutputMsg) []string {
+	var result = make([]string, len(messages))
+	for i, msg := range messages {
+		result[i] = msg.Message
+		if msg.Stream == "StdErr" {
+			result[i] = "(stderr) " + msg.Message
+		}
+	}
+
+	return result
+}
+
+func styledMessage(msg OutputMsg) string {
+	if msg.Stream == "StdErr" {
+		return bunt.Style(msg.Message, bunt.Blend(), bunt.Foreground(bunt.Red))
+	}
+
+	return msg.Message
+}
+
+func pluralize(noun string, count int) string {
+	if count == 1 {
+		return noun
+	}
+
+	return noun + "s"
+}
+
+// compactNames returns a list of names, where names that only differ in a
+// consecutive number are combined into a range, for example router-0..router-5
+func compactNames(names []string) string {
+	type numbered struct {
+		name   string
+		number int
+	}
+
+	var (
+		keys   []string
+		series = map[string][]numbered{}
+	)
+
+	for _, name := range names {
+		var key, number = name, -1
+		if match := numberedName.FindStringSubmatch(name); match != nil {
+			if n, err := strconv.Atoi(match[2]); err == nil {
+				key, number = match[1]+"\x00"+match[3], n
+			}
+		}
+
+		if _, ok := series[key]; !ok {
+			keys = append(keys, key)
+		}
+
+		series[key] = append(series[key], numbered{name, number})
+	}
+
+	var result []string
+	for _, key := range keys {
+		var entries = series[key]
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].number < entries[j].number
+		})
+
+		for i := 0; i < len(entries); {
+			var j = i
+			for j+1 < len(entries) && entries[j].number >= 0 && entries[j+1].number == entries[j].number+1 {
+				j++
+			}
+
+			switch {
+			case j-i >= 2:
+				result = append(result, entries[i].name+".."+entries[j].name)
+
+			default:
+				for k := i; k <= j; k++ {
+					result = append(result, entries[k].name)
+				}
+			}
+
+			i = j + 1
+		}
+	}
+
+	return strings.Join(result, ", ")
+}
+
+// diffLine is a line of a line based difference, where old and new are the
+// positions in the respective input before the line
+type diffLine struct {
+	kind     byte
+	text     string
+	old, new int
+}
+
+// lineDiff returns the lines of both inputs with the information whether a
+// line is unchanged, removed, or added, based on the longest common sequence
+func lineDiff(a, b []string) []diffLine {
+	var result []diffLine
+
+	// Unchanged lines at the start and the end do not need to be compared
+	var prefix = 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		result = append(result, diffLine{' ', a[prefix], prefix, prefix})
+		prefix++
+	}
+
+	var suffix = 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var x, y = a[prefix : len(a)-suffix], b[prefix : len(b)-suffix]
+	var n, m = len(x), len(y)
+
+	var lcs = make([][]int, n+1)
+	if (n+1)*(m+1) <= diffMaxCells {
+		for i := range lcs {
+			lcs[i] = make([]int, m+1)
+		}
+
+		for i := n - 1; i >= 0; i-- {
+			for j := m - 1; j >= 0; j-- {
+				if x[i] == y[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+	}
+
+	var i, j = 0, 0
+	for lcs[0] != nil && i < n && j < m {
+		switch {
+		case x[i] == y[j]:
+			result = append(result, diffLine{' ', x[i], prefix + i, prefix + j})
+			i++
+			j++
+
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, diffLine{'-', x[i], prefix + i, prefix + j})
+			i++
+
+		default:
+			result = append(result, diffLine{'+', y[j], prefix + i, prefix + j})
+			j++
+		}
+	}
+
+	for ; i < n; i++ {
+		result = append(result, diffLine{'-', x[i], prefix + i, prefix + j})
+	}
+
+	for ; j < m; j++ {
+		result = append(result, diffLine{'+', y[j], prefix + n, prefix + j})
+	}
+
+	for k := 0; k < suffix; k++ {
+		result = append(result, diffLine{' ', a[len(a)-suffix+k], len(a) - suffix + k, len(b) - suffix + k})
+	}
+
+	return result
+}
+
+// unifiedDiff returns the difference between both inputs in the unified
+// format, where the changes are shown with a few lines of context
+func unifiedDiff(a, b []string) string {
+	var lines = lineDiff(a, b)
+
+	// Mark all lines that are close enough to a change to be shown
+	var shown = make([]bool, len(lines))
+	for i, line := range lines {
+		if line.kind == ' ' {
+			continue
+		}
+
+		for k := max(i-diffContextLines, 0); k < len(lines) && k <= i+diffContextLines; k++ {
+			shown[k] = true
+		}
+	}
+
+	var result []string
+	for start := 0; start < len(lines); start++ {
+		if !shown[start] {
+			continue
+		}
+
+		var end = start
+		for end < len(lines) && shown[end] {
+			end++
+		}
+
+		var oldCount, newCount int
+		for _, line := range lines[start:end] {
+			if line.kind != '+' {
+				oldCount++
+			}
+
+			if line.kind != '-' {
+				newCount++
+			}
+		}
+
+		result = append(result, bunt.Style(
+			fmt.Sprintf("@@ -%s +%s @@", hunkRange(lines[start].old, oldCount), hunkRange(lines[start].new, newCount)),
+			bunt.Foreground(bunt.SkyBlue),
+		))
+
+		for _, line := range lines[start:end] {
+			switch line.kind {
+			case '-':
+				result = append(result, bunt.Style("-"+line.text, bunt.Foreground(bunt.Red)))
+
+			case '+':
+				result = append(result, bunt.Style("+"+line.text, bunt.Foreground(bunt.LimeGreen)))
+
+			default:
+				result = append(result, " "+line.text)
+			}
+		}
+
+		start = end
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// hunkRange returns the start and length of a hunk in the unified format,
+// where an empty range refers to the line before it
+func hunkRange(position int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", position)
+	}
+
+	return fmt.Sprintf("%d,%d", position+1, count)
+}
@@ -0,0 +1,132 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Supported output formats of the distributed command execution
+const (
+	outputFormatText  = "text"
+	outputFormatJSONL = "jsonl"
+)
+
+// jsonOutputMessage is the JSON lines record of an output message
+type jsonOutputMessage struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Cluster   string    `json:"cluster,omitempty"`
+	OutputTarget
+	Stream  string `json:"stream"`
+	Message string `json:"message"`
+}
+
+// jsonExecResult is the JSON lines record of the command execution result of
+// a target, where the exit code is null in case it is not known
+type jsonExecResult struct {
+	Type    string `json:"type"`
+	Cluster string `json:"cluster,omitempty"`
+	OutputTarget
+	ExitCode *int    `json:"exitCode"`
+	Duration float64 `json:"durationSeconds"`
+	Skipped  bool    `json:"skipped,omitempty"`
+	Canceled bool    `json:"canceled,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+func validateOutputFormat(format string) error {
+	switch format {
+	case "", outputFormatText, outputFormatJSONL:
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported output format %q, supported formats are %s and %s", format, outputFormatText, outputFormatJSONL)
+	}
+}
+
+// PrintOutputMessageJSON reads from the given output message channel and
+// prints each message as a JSON object in its own line
+func PrintOutputMessageJSON(messages chan OutputMsg) error {
+	var encoder = json.NewEncoder(os.Stdout)
+
+	var err error
+	for msg := range messages {
+		// Keep reading after a failure to not block the writers
+		if err != nil {
+			continue
+		}
+
+		err = encoder.Encode(jsonOutputMessage{
+			Type:         "output",
+			Timestamp:    msg.Timestamp,
+			Cluster:      msg.Cluster,
+			OutputTarget: msg.Target,
+			Stream:       strings.ToLower(msg.Stream),
+			Message:      msg.Message,
+		})
+	}
+
+	return err
+}
+
+// execResultRecords returns the JSON record of the command execution result
+// of each target
+func execResultRecords(summaries []execSummary) []jsonExecResult {
+	var records = make([]jsonExecResult, len(summaries))
+	for i, summary := range summaries {
+		records[i] = jsonExecResult{
+			Type:         "result",
+			Cluster:      summary.cluster,
+			OutputTarget: summary.target,
+			Duration:     summary.result.Duration.Seconds(),
+			Skipped:      summary.skipped,
+			Canceled:     summary.canceled,
+		}
+
+		if !summary.skipped && summary.result.ExitCode >= 0 {
+			records[i].ExitCode = &summary.result.ExitCode
+		}
+
+		if summary.err != nil {
+			records[i].Error = summary.err.Error()
+		}
+	}
+
+	return records
+}
+
+// printExecResultsJSON prints the command execution result of each target as
+// a JSON object in its own line
+func printExecResultsJSON(summaries []execSummary) error {
+	var encoder = json.NewEncoder(os.Stdout)
+	for _, record := range execResultRecords(summaries) {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -29,33 +29,106 @@ import (
 
 	"github.com/gonvenience/bunt"
 	"github.com/gonvenience/neat"
+	"github.com/homeport/havener/pkg/havener"
 	colorful "github.com/lucasb-eyer/go-colorful"
 )
 
 // OutputMsg is a message with additional context like time, an origin (name),
-// and stream (for example stdout).
+// and stream (for example stdout). In case multiple clusters are involved, the
+// cluster name is set to distinguish origins with the same name.
 type OutputMsg struct {
 	Timestamp time.Time
 	Stream    string
+	Cluster   string
 	Origin    string
+	Target    OutputTarget
 	Message   string
 }
 
-func chanWriter(stream string, origin string, c chan OutputMsg) io.Writer {
+// OutputTarget describes where output messages come from, which is either a
+// container of a pod, or a node
+type OutputTarget struct {
+	Namespace string `json:"namespace,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+	Container string `json:"container,omitempty"`
+	Node      string `json:"node,omitempty"`
+}
+
+// Name returns the name of the target that is used as the origin of output
+// messages, which is the pod and container name, or the node name
+func (t OutputTarget) Name() string {
+	if t.Node != "" {
+		return t.Node
+	}
+
+	return t.Pod + "/" + t.Container
+}
+
+// String returns the fully qualified name of the target
+func (t OutputTarget) String() string {
+	if t.Node != "" {
+		return t.Node
+	}
+
+	return t.Namespace + "/" + t.Pod + "/" + t.Container
+}
+
+func (msg OutputMsg) source() string {
+	return outputSource(msg.Cluster, msg.Origin)
+}
+
+// outputSource returns the name that identifies the output of an origin,
+// which is prefixed with the cluster name in case there is one
+func outputSource(cluster string, origin string) string {
+	if cluster == "" {
+		return origin
+	}
+
+	return cluster + "/" + origin
+}
+
+// chanPipeWriter is the writer end of an output message channel pipe, which
+// on close waits until all written content was sent to the channel
+type chanPipeWriter struct {
+	*io.PipeWriter
+	done chan struct{}
+}
+
+func (w chanPipeWriter) Close() error {
+	err := w.PipeWriter.Close()
+	<-w.done
+	return err
+}
+
+func chanWriter(stream string, cluster string, target OutputTarget, c chan OutputMsg) io.WriteCloser {
 	r, w := io.Pipe()
+	done := make(chan struct{})
 	go func() {
+		defer close(done)
 		scanner := bufio.NewScanner(r)
 		for scanner.Scan() {
 			c <- OutputMsg{
 				Timestamp: time.Now(),
 				Stream:    stream,
-				Origin:    origin,
+				Cluster:   cluster,
+				Origin:    target.Name(),
+				Target:    target,
 				Message:   scanner.Text(),
 			}
 		}
 	}()
 
-	return w
+	return chanPipeWriter{w, done}
+}
+
+// clusterOf returns the cluster name of the havener handle to be used in the
+// output messages, which is only required when more than one cluster is used
+func clusterOf(hvnr havener.Havener, multiCluster bool) string {
+	if !multiCluster {
+		return ""
+	}
+
+	return hvnr.ClusterName()
 }
 
 // PrintOutputMessage reads from the given output message channel and prints the
@@ -69,13 +142,13 @@ func PrintOutputMessage(messages chan OutputMsg) {
 	)
 
 	for msg := range messages {
-		if _, ok := originColors[msg.Origin]; !ok {
-			originColors[msg.Origin] = colors[originCounter]
+		if _, ok := originColors[msg.source()]; !ok {
+			originColors[msg.source()] = colors[originCounter]
 
 			originCounter = (originCounter + 1) % numberOfColors
 		}
 
-		printMessage(originColors[msg.Origin], msg)
+		printMessage(originColors[msg.source()], msg)
 	}
 }
 
@@ -93,12 +166,12 @@ func PrintOutputMessageAsBlock(messages chan OutputMsg) {
 	// Fully read the input channel and store the output messages indexed by the
 	// origin in a separate map
 	for msg := range messages {
-		if _, ok := data[msg.Origin]; !ok {
-			data[msg.Origin] = []OutputMsg{}
-			keys = append(keys, msg.Origin)
+		if _, ok := data[msg.source()]; !ok {
+			data[msg.source()] = []OutputMsg{}
+			keys = append(keys, msg.source())
 		}
 
-		data[msg.Origin] = append(data[msg.Origin], msg)
+		data[msg.source()] = append(data[msg.source()], msg)
 	}
 
 	sort.Slice(keys, func(i, j int) bool {
@@ -126,6 +199,11 @@ func printMessage(color colorful.Color, msg OutputMsg) {
 		message   = msg.Message
 	)
 
+	// Prefix the origin with the cluster name in case it is set
+	if msg.Cluster != "" {
+		prefix = bunt.Style(msg.Cluster+"/", bunt.Foreground(color), bunt.Italic()) + prefix
+	}
+
 	// Add a red tint to all error stream messages
 	if msg.Stream == "StdErr" {
 		message = bunt.Style(message,
@@ -168,3 +246,86 @@ func renderBoxWithTable(headline string, tablehead []string, table [][]string, s
 		neat.NoLineWrap(),
 	), nil
 }
+
+// execSummary is the outcome of a command execution on one target
+type execSummary struct {
+	cluster string
+	target  OutputTarget
+	result  havener.ExecResult
+	err     error
+
+	// skipped is set for targets that were not run, and canceled for targets
+	// that were stopped while running, both do not count as a failure
+	skipped  bool
+	canceled bool
+}
+
+// summaryExitCode returns the exit code for a distributed command execution,
+// which fails in case the command failed on at least one target
+func summaryExitCode(summaries []execSummary) int {
+	for _, summary := range summaries {
+		if summary.canceled {
+			continue
+		}
+
+		if summary.err != nil || summary.result.ExitCode != 0 {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// printExecSummary prints a table with the exit code of the command on each
+// target of a distributed command execution
+func printExecSummary(summaries []execSummary) error {
+	var multiCluster bool
+	for _, summary := range summaries {
+		if summary.cluster != "" {
+			multiCluster = true
+		}
+	}
+
+	var table [][]string
+	for _, summary := range summaries {
+		var row []string
+		if multiCluster {
+			row = append(row, summary.cluster)
+		}
+
+		row = append(row, summary.target.String())
+
+		switch {
+		case summary.skipped:
+			row = append(row, bunt.Sprintf("Gray{skipped}"), "", "")
+
+		case summary.canceled:
+			row = append(row, bunt.Sprintf("Gray{canceled}"), summary.result.Duration.Round(time.Millisecond).String(), "")
+
+		case summary.err != nil:
+			row = append(row, bunt.Sprintf("Red{failed}"), "", summary.err.Error())
+
+		case summary.result.ExitCode != 0:
+			row = append(row, bunt.Sprintf("Red{%d}", summary.result.ExitCode), summary.result.Duration.Round(time.Millisecond).String(), "")
+
+		default:
+			row = append(row, bunt.Sprintf("LimeGreen{0}"), summary.result.Duration.Round(time.Millisecond).String(), "")
+		}
+
+		table = append(table, row)
+	}
+
+	var tablehead = []string{"Target", "Exit Code", "Duration", "Error"}
+	if multiCluster {
+		tablehead = append([]string{"Cluster"}, tablehead...)
+	}
+
+	out, err := renderBoxWithTable("Summary", tablehead, table)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(out)
+	return nil
+}
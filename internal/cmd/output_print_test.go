@@ -21,9 +21,8 @@
 package cmd_test
 
 import (
-	"bytes"
-	"io"
-	"os"
+	"fmt"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -69,26 +68,6 @@ var exampleOutput = []OutputMsg{
 }
 
 var _ = Describe("Output message printing", func() {
-	var captureStdout = func(f func()) string {
-		r, w, err := os.Pipe()
-		Expect(err).ToNot(HaveOccurred())
-
-		tmp := os.Stdout
-		defer func() {
-			os.Stdout = tmp
-		}()
-
-		os.Stdout = w
-		f()
-		w.Close()
-
-		var buf bytes.Buffer
-		_, err = io.Copy(&buf, r)
-		Expect(err).ToNot(HaveOccurred())
-
-		return buf.String()
-	}
-
 	BeforeEach(func() {
 		SetColorSettings(OFF, OFF)
 		term.FixedTerminalWidth = 120
@@ -151,4 +130,104 @@ var _ = Describe("Output message printing", func() {
 
 		Expect(actual).To(BeEquivalentTo(expected))
 	})
+
+	It("should prefix the origin with the cluster name and keep clusters apart", func() {
+		exampleChannel := make(chan OutputMsg)
+		go func() {
+			for _, cluster := range []string{"two", "one"} {
+				exampleChannel <- OutputMsg{
+					Stream:    "StdOut",
+					Cluster:   cluster,
+					Origin:    "Prefix1",
+					Message:   "Hello from " + cluster,
+					Timestamp: time.Date(2019, time.July, 11, 8, 20, 16, 0, time.UTC),
+				}
+			}
+
+			close(exampleChannel)
+		}()
+
+		actual := captureStdout(func() {
+			PrintOutputMessageAsBlock(exampleChannel)
+		})
+
+		expected := "08:20:16 one/Prefix1 │ Hello from one\n" +
+			"08:20:16 two/Prefix1 │ Hello from two\n"
+
+		Expect(actual).To(BeEquivalentTo(expected))
+	})
+	Context("grouping identical output", func() {
+		var originsOf = func(outputs map[string]string) []string {
+			var origins []string
+			for origin := range outputs {
+				origins = append(origins, origin)
+			}
+
+			return origins
+		}
+
+		var outputOf = func(outputs map[string]string) chan OutputMsg {
+			var messages = make(chan OutputMsg)
+			go func() {
+				for origin, output := range outputs {
+					for _, line := range strings.Split(output, "\n") {
+						messages <- OutputMsg{Stream: "StdOut", Origin: origin, Message: line}
+					}
+				}
+
+				close(messages)
+			}()
+
+			return messages
+		}
+
+		It("should print identical output only once with a compact list of origins", func() {
+			var outputs = map[string]string{"db-0": "ID=debian"}
+			for i := 0; i < 6; i++ {
+				outputs[fmt.Sprintf("router-%d", i)] = "ID=alpine"
+			}
+
+			actual := captureStdout(func() {
+				PrintOutputMessageGrouped(outputOf(outputs), originsOf(outputs), "pod", false)
+			})
+
+			Expect(actual).To(ContainSubstring("router-0..router-5 (6 pods)"))
+			Expect(actual).To(ContainSubstring("db-0 (1 pod)"))
+			Expect(strings.Count(actual, "ID=alpine")).To(Equal(1))
+			Expect(strings.Index(actual, "ID=alpine")).To(BeNumerically("<", strings.Index(actual, "ID=debian")))
+		})
+
+		It("should show origins without any output as a group of their own", func() {
+			var outputs = map[string]string{"router-0": "ID=alpine", "router-1": "ID=alpine"}
+
+			actual := captureStdout(func() {
+				PrintOutputMessageGrouped(outputOf(outputs), []string{"router-0", "router-1", "router-2", "router-3"}, "pod", true)
+			})
+
+			Expect(actual).To(ContainSubstring("router-0, router-1 (2 pods)"))
+			Expect(actual).To(ContainSubstring("router-2, router-3 (2 pods)"))
+			Expect(actual).To(ContainSubstring("(no output)"))
+			Expect(actual).ToNot(ContainSubstring("difference to the majority output"))
+			Expect(strings.Count(actual, "ID=alpine")).To(Equal(1))
+		})
+
+		It("should show differing output as a diff against the majority output", func() {
+			var outputs = map[string]string{
+				"router-0": "a\nb\nc\nd\ne\nf\ng\nh",
+				"router-1": "a\nb\nc\nd\ne\nf\ng\nh",
+				"router-2": "a\nb\nc\nd\nX\nf\ng\nh",
+			}
+
+			actual := captureStdout(func() {
+				PrintOutputMessageGrouped(outputOf(outputs), originsOf(outputs), "pod", true)
+			})
+
+			Expect(actual).To(ContainSubstring("router-0, router-1 (2 pods)"))
+			Expect(actual).To(ContainSubstring("router-2 (1 pod), difference to the majority output"))
+			Expect(actual).To(ContainSubstring("@@ -2,7 +2,7 @@"))
+			Expect(actual).To(ContainSubstring("-e"))
+			Expect(actual).To(ContainSubstring("+X"))
+			Expect(actual).ToNot(ContainSubstring("│  a\n"))
+		})
+	})
 })
@@ -21,23 +21,26 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/gonvenience/bunt"
 	"github.com/homeport/havener/pkg/havener"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 const (
-	podExecDefaultCommand = "/bin/sh"
+	podExecDefaultCommand     = "/bin/sh"
+	podExecDefaultMaxParallel = 50
 )
 
 type target struct {
@@ -47,10 +50,26 @@ type target struct {
 }
 
 var podExecCmdSettings struct {
-	stdin        bool
-	tty          bool
-	notty        bool
-	printAsBlock bool
+	stdin         bool
+	tty           bool
+	notty         bool
+	printAsBlock  bool
+	group         bool
+	output        string
+	outputDir     string
+	quiet         bool
+	template      bool
+	script        string
+	diff          bool
+	selector      string
+	fieldSelector string
+	env           []string
+	workDir       string
+	execTimeout   time.Duration
+	stdinLimit    string
+	maxParallel   int
+	failFast      bool
+	canary        int
 }
 
 // podExecCmd represents the pod-exec command
@@ -77,9 +96,64 @@ command in the first container found in the pod.
 If you run the 'pod-exec' without any additional arguments, it will print a
 list of available pods.
 
+With one target, *havener* exits with the exit code of the command. With more
+than one target, a summary of the exit codes is shown at the end and *havener*
+fails if the command failed for at least one of the targets.
+
+With more than one target, the StdIn (_--stdin_) is read completely and the
+same input is passed to each pod container, for example to run a script using
+_havener pod-exec all -i -- sh < fix.sh_. Since the input is kept in memory,
+its size is limited, which can be changed using _--stdin-limit_.
+
+With _--group_, pod containers with identical output are combined, so that
+each distinct output is only shown once, for example for
+_havener pod-exec all -- cat /etc/os-release_. Pod containers without any
+output are shown as a group of their own. Use _--diff_ to show outputs that
+differ from the majority output as a diff against it.
+
+With more than one target, the command runs on at most %d pod containers in
+parallel, which can be changed using _--max-parallel_. For risky commands,
+_--fail-fast_ cancels the remaining targets after the first failure, and
+_--canary N_ runs the command on the first N targets and only continues with
+the rest in case it succeeded on all of them. Targets that were not run are
+shown as skipped in the summary, and targets that were stopped while running
+are shown as canceled.
+
 For convenience, if the target pod name _all_ is used, *havener* will look up
 all pods in all namespaces automatically.
-`, podExecDefaultCommand),
+
+With a label selector (_--selector_) or field selector (_--field-selector_),
+all containers of all matching pods are used as the target. In this case, the
+pod argument is omitted and all arguments are used as the command, for example
+_havener pod-exec -l app=router -- cat /etc/hosts_.
+
+Environment variables (_--env_) and a working directory (_--workdir_) are set
+by running the command through _/bin/sh_, which therefore has to be available
+in the container. With _--exec-timeout_, the command is aborted on each pod
+that does not finish in time, so that a hanging command does not block the
+other pods.
+
+With _--template_, each command argument is a Go template, which is expanded
+for each pod container right before the execution. The pod (_.Pod_), the
+container name (_.Container_), and the cluster name (_.Cluster_) are available,
+for example _havener pod-exec --template all -- echo '{{.Pod.Status.PodIP}}'_.
+
+With _--script_, the local script is uploaded into a temporary directory in
+each pod container, run there, and removed afterwards. All arguments after the
+pod are passed to the script, for example
+_havener pod-exec --script ./diag.sh all --verbose_. The container needs to
+provide _/bin/sh_, _mktemp_, and _tar_ for this.
+
+With _--output jsonl_, each output line is printed as a JSON object including
+the timestamp, stream, namespace, pod, and container, followed by one object
+per pod container with the exit code and error, so that the results can be
+processed by scripts. This output format is also used for a single target.
+
+With _--output-dir_, the standard output and error of each pod container are
+written into the files _<namespace>/<pod>/<container>.stdout_ and _.stderr_ in
+the given directory, together with a _summary.json_ file with the exit codes
+and durations. Use _--quiet_ to not show the command output in the terminal.
+`, podExecDefaultCommand, podExecDefaultMaxParallel),
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -94,12 +168,16 @@ all pods in all namespaces automatically.
 			podExecCmdSettings.tty = !podExecCmdSettings.notty
 		}
 
-		hvnr, err := havener.NewHavener(havener.WithContext(cmd.Context()), havener.WithKubeConfigPath(kubeConfig))
+		if err := validateOutputFormat(podExecCmdSettings.output); err != nil {
+			return err
+		}
+
+		hvnrs, err := newHaveners(cmd.Context())
 		if err != nil {
 			return fmt.Errorf("unable to get access to cluster: %w", err)
 		}
 
-		return execInClusterPods(hvnr, args)
+		return execInClusterPods(hvnrs, args)
 	},
 }
 
@@ -109,47 +187,112 @@ func init() {
 	podExecCmd.Flags().SortFlags = false
 	podExecCmd.Flags().BoolVarP(&podExecCmdSettings.stdin, "stdin", "i", false, "Pass stdin to the container")
 	podExecCmd.Flags().BoolVarP(&podExecCmdSettings.tty, "tty", "t", false, "Stdin is a TTY")
+	podExecCmd.Flags().StringVar(&podExecCmdSettings.stdinLimit, "stdin-limit", stdinDefaultLimit, "maximum size of stdin that is passed to more than one pod")
 	podExecCmd.Flags().BoolVar(&podExecCmdSettings.printAsBlock, "block", false, "show distributed shell output as block for each pod")
+	podExecCmd.Flags().BoolVar(&podExecCmdSettings.group, "group", false, "show distributed shell output once for all pod containers with identical output")
+	podExecCmd.Flags().BoolVar(&podExecCmdSettings.diff, "diff", false, "show differing output as a diff against the majority output (implies --group)")
+	podExecCmd.Flags().StringVarP(&podExecCmdSettings.output, "output", "o", outputFormatText, "output format of the distributed shell output, which is text or jsonl")
+	podExecCmd.Flags().StringVar(&podExecCmdSettings.outputDir, "output-dir", "", "write the output of each pod container into files in the given directory, including a summary.json")
+	podExecCmd.Flags().BoolVarP(&podExecCmdSettings.quiet, "quiet", "q", false, "do not show the command output in the terminal")
+	podExecCmd.Flags().StringVarP(&podExecCmdSettings.selector, "selector", "l", "", "label selector of the target pods, for example app=router")
+	podExecCmd.Flags().StringVar(&podExecCmdSettings.fieldSelector, "field-selector", "", "field selector of the target pods, for example spec.nodeName=node-0")
+	podExecCmd.Flags().StringArrayVar(&podExecCmdSettings.env, "env", nil, "environment variable in KEY=VALUE format for the command (can be used multiple times)")
+	podExecCmd.Flags().StringVar(&podExecCmdSettings.workDir, "workdir", "", "working directory for the command")
+	podExecCmd.Flags().IntVar(&podExecCmdSettings.maxParallel, "max-parallel", podExecDefaultMaxParallel, "number of parallel executions (value less or equal than zero means unlimited)")
+	podExecCmd.Flags().BoolVar(&podExecCmdSettings.failFast, "fail-fast", false, "cancel the remaining pods once the command failed on one pod")
+	podExecCmd.Flags().IntVar(&podExecCmdSettings.canary, "canary", 0, "run the command on the given number of pods first and only continue if it succeeded on all of them")
+	podExecCmd.Flags().DurationVar(&podExecCmdSettings.execTimeout, "exec-timeout", 0, "maximum duration of the command execution on each pod (zero means no limit)")
+	podExecCmd.Flags().BoolVar(&podExecCmdSettings.template, "template", false, "expand the command arguments as Go templates for each pod, for example {{.Pod.Name}}")
+	podExecCmd.Flags().StringVar(&podExecCmdSettings.script, "script", "", "upload the local script to each pod container and run it, remaining arguments are passed to the script")
 
 	// Deprecated/old flags
 	podExecCmd.Flags().BoolVar(&podExecCmdSettings.notty, "no-tty", false, "do not allocate pseudo-terminal for command execution")
 	_ = podExecCmd.Flags().MarkDeprecated("no-tty", "use --tty flag instead")
 }
 
-func execInClusterPods(hvnr havener.Havener, args []string) error {
+func execInClusterPods(hvnrs []havener.Havener, args []string) error {
+	type podTarget struct {
+		hvnr      havener.Havener
+		pod       *corev1.Pod
+		container string
+	}
+
 	var (
-		podMap          map[*corev1.Pod][]string
-		countContainers int
-		input           string
-		command         []string
-		err             error
+		targets []podTarget
+		input   string
+		command []string
 	)
 
+	var filter = havener.ListFilter{
+		LabelSelector: podExecCmdSettings.selector,
+		FieldSelector: podExecCmdSettings.fieldSelector,
+	}
+
+	var selected = filter.LabelSelector != "" || filter.FieldSelector != ""
+
+	// With a script, all arguments are passed to the script, so there is no
+	// default command
+	var defaultCommand = []string{podExecDefaultCommand}
+	if podExecCmdSettings.script != "" {
+		defaultCommand = nil
+	}
+
 	switch {
+	case selected && len(args) > 0: // pods are selected, only command is given
+		input, command = "all", args
+
+	case selected: // pods are selected, no command is given
+		input, command = "all", defaultCommand
+
 	case len(args) >= 2: // pod and command is given
 		input, command = args[0], args[1:]
-		podMap, err = lookupPodsByName(hvnr, input)
-		if err != nil {
+
+	case len(args) == 1: // only pod is given
+		input, command = args[0], defaultCommand
+
+	default:
+		return availablePodsError(hvnrs, nil, "no pod name specified")
+	}
+
+	// The script is uploaded using the standard input of the command
+	var upload *script
+	if podExecCmdSettings.script != "" {
+		if podExecCmdSettings.stdin {
+			return fmt.Errorf("cannot use --stdin and --script at the same time")
+		}
+
+		var err error
+		if upload, err = loadScript(podExecCmdSettings.script); err != nil {
 			return err
 		}
+	}
 
-	case len(args) == 1: // only pod is given
-		input, command = args[0], []string{podExecDefaultCommand}
-		podMap, err = lookupPodsByName(hvnr, input)
+	var pods int
+	for _, hvnr := range hvnrs {
+		podMap, err := lookupPodsByName(hvnr, input, filter)
 		if err != nil {
 			return err
 		}
 
-	default:
-		return availablePodsError(hvnr, "no pod name specified")
+		pods += len(podMap)
+		for pod, containers := range podMap {
+			for i := range containers {
+				targets = append(targets, podTarget{hvnr, pod, containers[i]})
+			}
+		}
 	}
 
-	// Count number of containers from all pods
-	// We only get all containers per pod, when "all" is specified
-	for _, containers := range podMap {
-		for range containers {
-			countContainers++
-		}
+	switch {
+	case selected && len(targets) == 0:
+		return availablePodsError(hvnrs, nil, "no pod matches the selector")
+
+	case len(targets) == 0:
+		return podNotFoundError(hvnrs, input)
+
+	case !selected && pods == 1 && !containerGiven(input):
+		// Only one pod matches and no container was given, therefore use the
+		// first container of the pod
+		targets = targets[:1]
 	}
 
 	if !isStdinTerminal() {
@@ -161,81 +304,272 @@ func execInClusterPods(hvnr havener.Havener, args []string) error {
 		in = os.Stdin
 	}
 
-	// Single pod mode, use default streams and run pod execute function
-	if len(podMap) == 1 {
-		for pod, containers := range podMap {
-			for i := range containers {
-				return hvnr.PodExec(
-					pod, containers[i],
-					havener.ExecConfig{
-						Command: command,
-						Stdin:   in,
-						Stdout:  os.Stdout,
-						Stderr:  os.Stderr,
-						TTY:     podExecCmdSettings.tty,
-					},
-				)
+	if upload != nil {
+		podExecCmdSettings.tty = false
+		in = upload.stdin()
+	}
+
+	// With templates enabled, the command is expanded for each target right
+	// before the execution
+	var commandFor = func(podTarget) ([]string, error) { return command, nil }
+	if podExecCmdSettings.template {
+		tmpl, err := newCommandTemplate(command)
+		if err != nil {
+			return err
+		}
+
+		commandFor = func(target podTarget) ([]string, error) {
+			return tmpl.expand(commandTemplateData{
+				Cluster:   target.hvnr.ClusterName(),
+				Pod:       target.pod,
+				Container: target.container,
+			})
+		}
+	}
+
+	if upload != nil {
+		var argsFor = commandFor
+		commandFor = func(target podTarget) ([]string, error) {
+			args, err := argsFor(target)
+			if err != nil {
+				return nil, err
 			}
+
+			return upload.command(args), nil
 		}
 	}
 
-	// In distributed shell mode, TTY is forced to be disabled
-	podExecCmdSettings.stdin = false
+	// Single pod mode, use default streams and run pod execute function, unless
+	// the output is meant to be processed or stored
+	if len(targets) == 1 && podExecCmdSettings.output != outputFormatJSONL && podExecCmdSettings.outputDir == "" && !podExecCmdSettings.quiet {
+		command, err := commandFor(targets[0])
+		if err != nil {
+			return err
+		}
+
+		result, err := targets[0].hvnr.PodExecWithResult(
+			targets[0].pod, targets[0].container,
+			havener.ExecConfig{
+				Command: command,
+				Stdin:   in,
+				Stdout:  os.Stdout,
+				Stderr:  os.Stderr,
+				TTY:     podExecCmdSettings.tty,
+				Env:     podExecCmdSettings.env,
+				WorkDir: podExecCmdSettings.workDir,
+				Timeout: podExecCmdSettings.execTimeout,
+			},
+		)
+
+		if err != nil {
+			return err
+		}
+
+		return exitWithCode(result.ExitCode)
+	}
+
+	// In distributed shell mode, TTY is forced to be disabled and the standard
+	// input is read once to pass the same input to each pod
 	podExecCmdSettings.tty = false
 
+	var stdin = func() io.Reader { return nil }
+	if podExecCmdSettings.stdin {
+		input, err := replayableInput(os.Stdin, podExecCmdSettings.stdinLimit)
+		if err != nil {
+			return err
+		}
+
+		stdin = input
+	}
+
+	if upload != nil {
+		stdin = upload.stdin
+	}
+
+	// In case the user wants everything done in parallel, increase the max value
+	if podExecCmdSettings.maxParallel <= 0 {
+		podExecCmdSettings.maxParallel = len(targets)
+	}
+
 	var (
-		wg      = &sync.WaitGroup{}
-		output  = make(chan OutputMsg)
-		errors  = make(chan error, countContainers)
-		printer = make(chan bool, 1)
-		counter = 0
+		output       = make(chan OutputMsg)
+		errors       = make(chan error, len(targets))
+		printer      = make(chan bool, 1)
+		multiCluster = len(hvnrs) > 1
+		summaries    = make([]execSummary, len(targets))
 	)
 
-	for pod, containers := range podMap {
-		for i := range containers {
+	// Remaining targets are skipped and running ones are stopped once the
+	// context is canceled, which happens in fail-fast or canary mode, or when
+	// the command itself is canceled (all handles share the command context)
+	ctx, cancel := context.WithCancel(hvnrs[0].Context())
+	defer cancel()
+
+	// run executes the command on the given targets using a pool of workers
+	var run = func(indices []int) {
+		var wg = &sync.WaitGroup{}
+		var queue = make(chan int, len(indices))
+		for _, i := range indices {
+			queue <- i
+		}
+		close(queue)
+
+		for range min(podExecCmdSettings.maxParallel, len(indices)) {
 			wg.Add(1)
-			go func(pod *corev1.Pod, container string) {
+			go func() {
 				defer wg.Done()
-				origin := fmt.Sprintf("%s/%s", pod.Name, container)
-				errors <- hvnr.PodExec(
-					pod, container,
-					havener.ExecConfig{
-						Command: command,
-						Stdin:   nil, // Disabled for now until reliable input duplication works
-						Stdout:  chanWriter("StdOut", origin, output),
-						Stderr:  chanWriter("StdErr", origin, output),
-						TTY:     podExecCmdSettings.tty,
-					},
-				)
-			}(pod, containers[i])
-			counter++
+				for i := range queue {
+					hvnr, pod, container := targets[i].hvnr, targets[i].pod, targets[i].container
+					cluster := clusterOf(hvnr, multiCluster)
+					target := OutputTarget{Namespace: pod.Namespace, Pod: pod.Name, Container: container}
+					summaries[i] = execSummary{cluster: cluster, target: target}
+
+					if ctx.Err() != nil {
+						summaries[i].skipped = true
+						continue
+					}
+
+					command, err := commandFor(targets[i])
+					if err != nil {
+						summaries[i].err = err
+						errors <- err
+						continue
+					}
+
+					stdout, stderr, err := targetWriters(cluster, target, output, podExecCmdSettings.outputDir, podExecCmdSettings.quiet)
+					if err != nil {
+						summaries[i].err = err
+						errors <- err
+						continue
+					}
+
+					result, err := hvnr.PodExecWithResult(
+						pod, container,
+						havener.ExecConfig{
+							Command: command,
+							Stdin:   stdin(),
+							Stdout:  stdout,
+							Stderr:  stderr,
+							TTY:     podExecCmdSettings.tty,
+							Env:     podExecCmdSettings.env,
+							WorkDir: podExecCmdSettings.workDir,
+							Timeout: podExecCmdSettings.execTimeout,
+							Context: ctx,
+						},
+					)
+
+					_ = stdout.Close()
+					_ = stderr.Close()
+
+					// A command that never terminated because it was stopped
+					// after the failure on another target did not fail itself
+					if err != nil && result.ExitCode < 0 && ctx.Err() != nil {
+						summaries[i].result, summaries[i].canceled = result, true
+						continue
+					}
+
+					if podExecCmdSettings.failFast && (err != nil || result.ExitCode != 0) {
+						cancel()
+					}
+
+					summaries[i].result, summaries[i].err = result, err
+					errors <- err
+				}
+			}()
 		}
+
+		wg.Wait()
 	}
 
 	// Start the respective output printer in a separate Go routine
+	var printErr error
 	go func() {
-		if podExecCmdSettings.printAsBlock {
+		switch {
+		case podExecCmdSettings.output == outputFormatJSONL:
+			printErr = PrintOutputMessageJSON(output)
+
+		case podExecCmdSettings.group || podExecCmdSettings.diff:
+			var sources = make([]string, len(targets))
+			for i, t := range targets {
+				sources[i] = outputSource(clusterOf(t.hvnr, multiCluster), OutputTarget{Pod: t.pod.Name, Container: t.container}.Name())
+			}
+
+			PrintOutputMessageGrouped(output, sources, "container", podExecCmdSettings.diff)
+
+		case podExecCmdSettings.printAsBlock:
 			PrintOutputMessageAsBlock(output)
 
-		} else {
+		default:
 			PrintOutputMessage(output)
 		}
 
 		printer <- true
 	}()
 
-	wg.Wait()
+	var indices = make([]int, len(targets))
+	for i := range indices {
+		indices[i] = i
+	}
+
+	// In canary mode, only continue with the remaining targets in case the
+	// command succeeded on all canary targets
+	var canary = min(max(podExecCmdSettings.canary, 0), len(targets))
+	if canary > 0 {
+		run(indices[:canary])
+		if summaryExitCode(summaries[:canary]) != 0 {
+			cancel()
+		}
+	}
+
+	run(indices[canary:])
+
 	close(errors)
 	close(output)
+	<-printer
 
-	if viper.GetBool("verbose") {
-		if err := combineErrorsFromChannel("pod command execution failed", errors); err != nil {
+	if podExecCmdSettings.outputDir != "" {
+		if err := writeSummaryFile(podExecCmdSettings.outputDir, summaries); err != nil {
 			return err
 		}
 	}
 
-	<-printer
-	return nil
+	switch podExecCmdSettings.output {
+	case outputFormatJSONL:
+		if printErr != nil {
+			return printErr
+		}
+
+		if err := printExecResultsJSON(summaries); err != nil {
+			return err
+		}
+
+	default:
+		if err := printExecSummary(summaries); err != nil {
+			return err
+		}
+	}
+
+	if err := hvnrs[0].Context().Err(); err != nil {
+		return fmt.Errorf("pod command execution canceled: %w", err)
+	}
+
+	if err := combineErrorsFromChannel("pod command execution failed", errors); err != nil {
+		return err
+	}
+
+	return exitWithCode(summaryExitCode(summaries))
+}
+
+// containerGiven returns whether the pod input names a container, which is
+// the case for input in the form namespace/pod/container
+func containerGiven(input string) bool {
+	for _, str := range strings.Split(input, ",") {
+		if strings.Count(str, "/") == 2 {
+			return true
+		}
+	}
+
+	return false
 }
 
 func containerNames(pod *corev1.Pod) []string {
@@ -255,12 +589,12 @@ func (t target) String() string {
 	)
 }
 
-func lookupPodsByName(h havener.Havener, input string) (map[*corev1.Pod][]string, error) {
+func lookupPodsByName(h havener.Havener, input string, filter havener.ListFilter) (map[*corev1.Pod][]string, error) {
 	var targets = map[*corev1.Pod][]string{}
 
 	// In case special term `all` is used, immediately return the full list of all pod containers
 	if input == "all" {
-		list, err := h.ListPods()
+		list, err := h.ListPodsWithOptions(filter)
 		if err != nil {
 			return nil, err
 		}
@@ -281,21 +615,21 @@ func lookupPodsByName(h havener.Havener, input string) (map[*corev1.Pod][]string
 		case 1: // only the pod name is given
 			namespace, podName, containerName := "*", splited[0], "*"
 			candidates = append(candidates, target{namespace, podName, containerName})
-			if err := updateLookUps(h, &keys, lookUp, namespace); err != nil {
+			if err := updateLookUps(h, &keys, lookUp, namespace, filter); err != nil {
 				return nil, err
 			}
 
 		case 2: // namespace, and pod name is given
 			namespace, podName, containerName := splited[0], splited[1], "*"
 			candidates = append(candidates, target{namespace, podName, containerName})
-			if err := updateLookUps(h, &keys, lookUp, namespace); err != nil {
+			if err := updateLookUps(h, &keys, lookUp, namespace, filter); err != nil {
 				return nil, err
 			}
 
 		case 3: // namespace, pod, and container name is given
 			namespace, podName, containerName := splited[0], splited[1], splited[2]
 			candidates = append(candidates, target{namespace, podName, containerName})
-			if err := updateLookUps(h, &keys, lookUp, namespace); err != nil {
+			if err := updateLookUps(h, &keys, lookUp, namespace, filter); err != nil {
 				return nil, err
 			}
 
@@ -321,13 +655,12 @@ func lookupPodsByName(h havener.Havener, input string) (map[*corev1.Pod][]string
 	return targets, nil
 }
 
-func updateLookUps(h havener.Havener, keys *[]target, lookUp map[target]*corev1.Pod, namespace string) error {
-	var namespaces []string
+func updateLookUps(h havener.Havener, keys *[]target, lookUp map[target]*corev1.Pod, namespace string, filter havener.ListFilter) error {
 	if namespace != "*" {
-		namespaces = append(namespaces, namespace)
+		filter.Namespaces = []string{namespace}
 	}
 
-	list, err := h.ListPods(namespaces...)
+	list, err := h.ListPodsWithOptions(filter)
 	if err != nil {
 		return err
 	}
@@ -343,24 +676,43 @@ func updateLookUps(h havener.Havener, keys *[]target, lookUp map[target]*corev1.
 	return nil
 }
 
-func availablePodsError(h havener.Havener, format string, a ...any) error {
-	pods, err := h.ListPods()
-	if err != nil {
-		return fmt.Errorf("failed to list all pods in cluster: %w", err)
-	}
-
+// availablePodsError returns an error with the list of available pods, the
+// cause (if set) is part of the error chain, for example to describe that a
+// pod does not exist
+func availablePodsError(hvnrs []havener.Havener, cause error, format string, a ...any) error {
 	var targets []string
-	for _, pod := range pods {
-		for _, container := range pod.Spec.Containers {
-			target := target{pod.Namespace, pod.Name, container.Name}
-			targets = append(targets, target.String())
+	for _, h := range hvnrs {
+		pods, err := h.ListPods()
+		if err != nil {
+			return fmt.Errorf("failed to list all pods in cluster: %w", err)
+		}
+
+		for _, pod := range pods {
+			for _, container := range pod.Spec.Containers {
+				target := target{pod.Namespace, pod.Name, container.Name}
+				if len(hvnrs) > 1 {
+					targets = append(targets, h.ClusterName()+": "+target.String())
+					continue
+				}
+
+				targets = append(targets, target.String())
+			}
 		}
 	}
 
-	return fmt.Errorf("%s: %w",
-		fmt.Sprintf(format, a...),
-		fmt.Errorf("List of available pods:\n%s",
-			strings.Join(targets, "\n"),
-		),
+	var details = fmt.Sprintf("List of available pods:\n%s", strings.Join(targets, "\n"))
+	if cause == nil {
+		return fmt.Errorf("%s: %w", fmt.Sprintf(format, a...), errors.New(details))
+	}
+
+	return fmt.Errorf("%s: %w", fmt.Sprintf(format, a...), fmt.Errorf("%w\n\n%s", cause, details))
+}
+
+// podNotFoundError returns the error for pod names that do not match any pod,
+// including the list of available pods
+func podNotFoundError(hvnrs []havener.Havener, input string) error {
+	return availablePodsError(hvnrs,
+		&havener.ResourceNotFoundError{Kind: havener.KindPod, Name: input},
+		"no pod matches %s", input,
 	)
 }
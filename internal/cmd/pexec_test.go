@@ -0,0 +1,537 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gonvenience/bunt"
+	. "github.com/homeport/havener/internal/cmd"
+
+	"github.com/homeport/havener/pkg/havener"
+	"github.com/homeport/havener/pkg/havener/havenertest"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/exec"
+)
+
+var _ = Describe("pod-exec", func() {
+	var hvnr *havenertest.Havener
+
+	BeforeEach(func() {
+		SetColorSettings(OFF, OFF)
+
+		hvnr = havenertest.NewHavener(
+			exampleNamespace("default"),
+			exampleNamespace("kube-system"),
+			examplePod("default", "api-0", "api", "sidecar"),
+			examplePod("default", "api-1", "api", "sidecar"),
+			examplePod("kube-system", "dns-0", "dns"),
+		)
+	})
+
+	AfterEach(func() {
+		SetColorSettings(AUTO, AUTO)
+	})
+
+	Context("looking up pods by name", func() {
+		It("should find all pods and containers using the special term all", func() {
+			targets, err := LookupPodsByName(hvnr, "all", havener.ListFilter{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(targets).To(HaveLen(3))
+		})
+
+		It("should find pods using wildcards and namespaces", func() {
+			targets, err := LookupPodsByName(hvnr, "default/api-*/sidecar", havener.ListFilter{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(targets).To(HaveLen(2))
+			for pod, containers := range targets {
+				Expect(pod.Namespace).To(Equal("default"))
+				Expect(containers).To(Equal([]string{"sidecar"}))
+			}
+		})
+
+		It("should only consider pods that match the label selector", func() {
+			pod, err := hvnr.Client().CoreV1().Pods("default").Get(hvnr.Context(), "api-1", metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			pod.Labels = map[string]string{"app": "api"}
+			_, err = hvnr.Client().CoreV1().Pods("default").Update(hvnr.Context(), pod, metav1.UpdateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			targets, err := LookupPodsByName(hvnr, "all", havener.ListFilter{LabelSelector: "app=api"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(targets).To(HaveLen(1))
+			for pod := range targets {
+				Expect(pod.Name).To(Equal("api-1"))
+			}
+
+			targets, err = LookupPodsByName(hvnr, "default/api-*/api", havener.ListFilter{LabelSelector: "app=api"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(targets).To(HaveLen(1))
+		})
+
+		It("should fail for an unsupported naming schema", func() {
+			_, err := LookupPodsByName(hvnr, "a/b/c/d", havener.ListFilter{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("executing commands in pods", func() {
+		It("should execute the command in a single pod container using the standard streams", func() {
+			hvnr.Exec = havenertest.Reply("hello\n", "", 0)
+
+			out := captureStdout(func() {
+				Expect(ExecInClusterPods([]havener.Havener{hvnr}, []string{"dns-0", "echo", "hello"})).To(Succeed())
+			})
+
+			Expect(out).To(Equal("hello\n"))
+			Expect(hvnr.Executions()).To(Equal([]string{"kube-system/dns-0/dns"}))
+		})
+
+		It("should execute the command in the first container of a single pod with more than one container", func() {
+			hvnr.Exec = havenertest.Reply("hello\n", "", 0)
+
+			out := captureStdout(func() {
+				Expect(ExecInClusterPods([]havener.Havener{hvnr}, []string{"api-0", "echo", "hello"})).To(Succeed())
+			})
+
+			Expect(out).To(Equal("hello\n"))
+			Expect(hvnr.Executions()).To(Equal([]string{"default/api-0/api"}))
+		})
+
+		It("should execute the command in all containers of a single pod when the container is a wildcard", func() {
+			hvnr.Exec = havenertest.Reply("hello\n", "", 0)
+
+			captureStdout(func() {
+				Expect(ExecInClusterPods([]havener.Havener{hvnr}, []string{"default/api-0/*", "echo", "hello"})).To(Succeed())
+			})
+
+			Expect(hvnr.Executions()).To(ConsistOf("default/api-0/api", "default/api-0/sidecar"))
+		})
+
+		It("should execute the command in all matching pod containers", func() {
+			hvnr.Exec = func(target havenertest.ExecTarget, execConfig havener.ExecConfig) error {
+				_, err := execConfig.Stdout.Write([]byte(strings.Join(execConfig.Command, " ") + "\n"))
+				return err
+			}
+
+			out := captureStdout(func() {
+				Expect(ExecInClusterPods([]havener.Havener{hvnr}, []string{"default/api-*", "echo", "hello"})).To(Succeed())
+			})
+
+			Expect(hvnr.Executions()).To(ConsistOf(
+				"default/api-0/api",
+				"default/api-0/sidecar",
+				"default/api-1/api",
+				"default/api-1/sidecar",
+			))
+
+			for _, origin := range []string{"api-0/api", "api-0/sidecar", "api-1/api", "api-1/sidecar"} {
+				Expect(out).To(ContainSubstring(origin + " │ echo hello\n"))
+			}
+		})
+
+		It("should prefix the output with the cluster name when multiple clusters are used", func() {
+			other := havenertest.NewHavenerWithContext(hvnr.Context(), "other",
+				exampleNamespace("default"),
+				examplePod("default", "api-0", "api"),
+			)
+
+			for _, h := range []*havenertest.Havener{hvnr, other} {
+				h.Exec = havenertest.Reply("hello\n", "", 0)
+			}
+
+			out := captureStdout(func() {
+				Expect(ExecInClusterPods([]havener.Havener{hvnr, other}, []string{"default/api-0/api", "echo", "hello"})).To(Succeed())
+			})
+
+			Expect(out).To(ContainSubstring("fake/api-0/api │ hello\n"))
+			Expect(out).To(ContainSubstring("other/api-0/api │ hello\n"))
+		})
+
+		It("should exit with the exit code of the command in a single pod container", func() {
+			hvnr.Exec = havenertest.Reply("", "", 42)
+
+			err := ExecInClusterPods([]havener.Havener{hvnr}, []string{"dns-0", "exit", "42"})
+			Expect(ExitCodeOf(err)).To(Equal(42))
+		})
+
+		It("should print a summary of the exit codes of all pod containers", func() {
+			hvnr.Exec = havenertest.Reply("", "", 0)
+
+			var err error
+			out := captureStdout(func() {
+				err = ExecInClusterPods([]havener.Havener{hvnr}, []string{"default/api-*", "true"})
+			})
+
+			Expect(err).ToNot(HaveOccurred())
+			for _, target := range []string{"default/api-0/api", "default/api-0/sidecar", "default/api-1/api", "default/api-1/sidecar"} {
+				Expect(out).To(MatchRegexp(target + `\s+0\s`))
+			}
+		})
+
+		It("should limit the number of parallel executions", func() {
+			DeferCleanup(SetPodExecParallelism(2, false, 0))
+
+			var running, peak atomic.Int32
+			hvnr.Exec = func(_ havenertest.ExecTarget, _ havener.ExecConfig) error {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+
+				time.Sleep(10 * time.Millisecond)
+				running.Add(-1)
+				return nil
+			}
+
+			captureStdout(func() {
+				Expect(ExecInClusterPods([]havener.Havener{hvnr}, []string{"all", "true"})).To(Succeed())
+			})
+
+			Expect(hvnr.Executions()).To(HaveLen(5))
+			Expect(peak.Load()).To(BeNumerically("<=", 2))
+		})
+
+		It("should skip the remaining pod containers after the first failure in fail-fast mode", func() {
+			DeferCleanup(SetPodExecParallelism(1, true, 0))
+			hvnr.Exec = havenertest.Reply("", "", 1)
+
+			var err error
+			out := captureStdout(func() {
+				err = ExecInClusterPods([]havener.Havener{hvnr}, []string{"all", "false"})
+			})
+
+			Expect(ExitCodeOf(err)).To(Equal(1))
+			Expect(hvnr.Executions()).To(HaveLen(1))
+			Expect(strings.Count(out, "skipped")).To(Equal(4))
+		})
+
+		It("should report running pod containers as canceled after the first failure in fail-fast mode", func() {
+			DeferCleanup(SetPodExecParallelism(0, true, 0))
+
+			var calls atomic.Int32
+			hvnr.Exec = func(_ havenertest.ExecTarget, execConfig havener.ExecConfig) error {
+				if calls.Add(1) == 1 {
+					time.Sleep(10 * time.Millisecond)
+					return exec.CodeExitError{Err: errors.New("command terminated with exit code 1"), Code: 1}
+				}
+
+				<-execConfig.Context.Done()
+				return execConfig.Context.Err()
+			}
+
+			var err error
+			out := captureStdout(func() {
+				err = ExecInClusterPods([]havener.Havener{hvnr}, []string{"all", "false"})
+			})
+
+			Expect(ExitCodeOf(err)).To(Equal(1))
+			Expect(hvnr.Executions()).To(HaveLen(5))
+			Expect(strings.Count(out, "canceled")).To(Equal(4))
+			Expect(out).ToNot(ContainSubstring("failed"))
+		})
+
+		It("should skip all pod containers when the command is canceled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			hvnr := havenertest.NewHavenerWithContext(ctx, havenertest.DefaultClusterName,
+				examplePod("default", "api-0", "api"),
+				examplePod("default", "api-1", "api"),
+			)
+
+			var err error
+			out := captureStdout(func() {
+				err = ExecInClusterPods([]havener.Havener{hvnr}, []string{"all", "true"})
+			})
+
+			Expect(err).To(MatchError(context.Canceled))
+			Expect(hvnr.Executions()).To(BeEmpty())
+			Expect(strings.Count(out, "skipped")).To(Equal(2))
+		})
+
+		It("should return the errors of the pod containers that could not run the command", func() {
+			hvnr.Exec = func(_ havenertest.ExecTarget, _ havener.ExecConfig) error {
+				return errors.New("connection lost")
+			}
+
+			var err error
+			captureStdout(func() {
+				err = ExecInClusterPods([]havener.Havener{hvnr}, []string{"default/api-*", "true"})
+			})
+
+			Expect(err).To(MatchError(ContainSubstring("pod command execution failed")))
+			Expect(err).To(MatchError(ContainSubstring("connection lost")))
+		})
+
+		It("should only continue with the remaining pod containers when the canaries succeed", func() {
+			DeferCleanup(SetPodExecParallelism(0, false, 2))
+			hvnr.Exec = havenertest.Reply("", "", 0)
+
+			captureStdout(func() {
+				Expect(ExecInClusterPods([]havener.Havener{hvnr}, []string{"all", "true"})).To(Succeed())
+			})
+
+			Expect(hvnr.Executions()).To(HaveLen(5))
+		})
+
+		It("should skip the remaining pod containers when a canary fails", func() {
+			DeferCleanup(SetPodExecParallelism(0, false, 2))
+			hvnr.Exec = havenertest.Reply("", "", 3)
+
+			var err error
+			out := captureStdout(func() {
+				err = ExecInClusterPods([]havener.Havener{hvnr}, []string{"all", "false"})
+			})
+
+			Expect(ExitCodeOf(err)).To(Equal(1))
+			Expect(hvnr.Executions()).To(HaveLen(2))
+			Expect(strings.Count(out, "skipped")).To(Equal(3))
+		})
+
+		It("should pass the same standard input to all pod containers", func() {
+			DeferCleanup(SetPodExecStdin(true, "1Ki"))
+
+			var inputs sync.Map
+			hvnr.Exec = func(target havenertest.ExecTarget, execConfig havener.ExecConfig) error {
+				Expect(execConfig.Stdin).ToNot(BeNil())
+				data, err := io.ReadAll(execConfig.Stdin)
+				inputs.Store(target.String(), string(data))
+				return err
+			}
+
+			withStdin("echo fix\n", func() {
+				captureStdout(func() {
+					Expect(ExecInClusterPods([]havener.Havener{hvnr}, []string{"all", "sh"})).To(Succeed())
+				})
+			})
+
+			var count int
+			inputs.Range(func(_, value any) bool {
+				Expect(value).To(Equal("echo fix\n"))
+				count++
+				return true
+			})
+
+			Expect(count).To(Equal(5))
+		})
+
+		It("should fail when the standard input exceeds the limit", func() {
+			DeferCleanup(SetPodExecStdin(true, "8"))
+
+			withStdin("more than eight bytes", func() {
+				err := ExecInClusterPods([]havener.Havener{hvnr}, []string{"all", "sh"})
+				Expect(err).To(MatchError(ContainSubstring("standard input exceeds the limit of 8.0 Byte")))
+			})
+
+			Expect(hvnr.Executions()).To(BeEmpty())
+		})
+
+		It("should print JSON lines with the output and the result even for a single pod container", func() {
+			DeferCleanup(SetExecOutput("jsonl"))
+			hvnr.Exec = havenertest.Reply("hello\n", "oops\n", 3)
+
+			var err error
+			out := captureStdout(func() {
+				err = ExecInClusterPods([]havener.Havener{hvnr}, []string{"dns-0", "cat", "/foo"})
+			})
+
+			Expect(ExitCodeOf(err)).To(Equal(1))
+
+			var records []map[string]any
+			for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+				var record map[string]any
+				Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+				records = append(records, record)
+			}
+
+			Expect(records).To(HaveLen(3))
+			Expect(records).To(ContainElement(SatisfyAll(
+				HaveKeyWithValue("type", "output"),
+				HaveKeyWithValue("namespace", "kube-system"),
+				HaveKeyWithValue("pod", "dns-0"),
+				HaveKeyWithValue("container", "dns"),
+				HaveKeyWithValue("stream", "stdout"),
+				HaveKeyWithValue("message", "hello"),
+				HaveKey("timestamp"),
+			)))
+
+			Expect(records).To(ContainElement(SatisfyAll(
+				HaveKeyWithValue("stream", "stderr"),
+				HaveKeyWithValue("message", "oops"),
+			)))
+
+			Expect(records[2]).To(SatisfyAll(
+				HaveKeyWithValue("type", "result"),
+				HaveKeyWithValue("pod", "dns-0"),
+				HaveKeyWithValue("exitCode", BeNumerically("==", 3)),
+			))
+		})
+
+		It("should write the output of each pod container into files of the output directory", func() {
+			dir := GinkgoT().TempDir()
+			DeferCleanup(SetExecOutputDir(dir, true))
+			hvnr.Exec = havenertest.Reply("hello\n", "oops\n", 0)
+
+			out := captureStdout(func() {
+				Expect(ExecInClusterPods([]havener.Havener{hvnr}, []string{"all", "echo", "hello"})).To(Succeed())
+			})
+
+			Expect(out).ToNot(ContainSubstring("hello"))
+			Expect(filepath.Join(dir, "default", "api-0", "sidecar.stdout")).To(BeARegularFile())
+			Expect(os.ReadFile(filepath.Join(dir, "kube-system", "dns-0", "dns.stdout"))).To(BeEquivalentTo("hello\n"))
+			Expect(os.ReadFile(filepath.Join(dir, "kube-system", "dns-0", "dns.stderr"))).To(BeEquivalentTo("oops\n"))
+
+			data, err := os.ReadFile(filepath.Join(dir, "summary.json"))
+			Expect(err).ToNot(HaveOccurred())
+
+			var summary []map[string]any
+			Expect(json.Unmarshal(data, &summary)).To(Succeed())
+			Expect(summary).To(HaveLen(5))
+			Expect(summary).To(ContainElement(SatisfyAll(
+				HaveKeyWithValue("namespace", "kube-system"),
+				HaveKeyWithValue("pod", "dns-0"),
+				HaveKeyWithValue("container", "dns"),
+				HaveKeyWithValue("exitCode", BeNumerically("==", 0)),
+				HaveKey("durationSeconds"),
+			)))
+		})
+
+		It("should expand the command template for each pod container", func() {
+			DeferCleanup(SetExecTemplate(true))
+
+			var lock sync.Mutex
+			var commands = map[string]string{}
+			hvnr.Exec = func(target havenertest.ExecTarget, execConfig havener.ExecConfig) error {
+				lock.Lock()
+				defer lock.Unlock()
+				commands[target.String()] = strings.Join(execConfig.Command, " ")
+				return nil
+			}
+
+			captureStdout(func() {
+				Expect(ExecInClusterPods([]havener.Havener{hvnr}, []string{"default/api-*", "echo", "{{.Pod.Name}}/{{.Container}}", "{}"})).To(Succeed())
+			})
+
+			Expect(commands).To(HaveKeyWithValue("default/api-0/sidecar", "echo api-0/sidecar {}"))
+			Expect(commands).To(HaveKeyWithValue("default/api-1/api", "echo api-1/api {}"))
+		})
+
+		It("should keep literal braces when templates are not enabled", func() {
+			hvnr.Exec = func(_ havenertest.ExecTarget, execConfig havener.ExecConfig) error {
+				Expect(execConfig.Command).To(Equal([]string{"echo", "{{.Pod.Name}}"}))
+				return nil
+			}
+
+			Expect(ExecInClusterPods([]havener.Havener{hvnr}, []string{"dns-0", "echo", "{{.Pod.Name}}"})).To(Succeed())
+		})
+
+		It("should fail for an invalid command template before executing anything", func() {
+			DeferCleanup(SetExecTemplate(true))
+
+			err := ExecInClusterPods([]havener.Havener{hvnr}, []string{"all", "echo", "{{.Pod.Name"})
+			Expect(err).To(MatchError(ContainSubstring("failed to parse command argument")))
+			Expect(hvnr.Executions()).To(BeEmpty())
+		})
+
+		It("should upload and run a script with arguments in each pod container", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "diag.sh")
+			Expect(os.WriteFile(path, []byte("echo \"$(basename \"$0\") $*\"\nexit 7\n"), 0644)).To(Succeed())
+			DeferCleanup(SetExecScript(path))
+
+			// Run the command locally to verify the upload and clean-up
+			var lock sync.Mutex
+			var dirs []string
+			hvnr.Exec = func(_ havenertest.ExecTarget, execConfig havener.ExecConfig) error {
+				Expect(execConfig.Command[:2]).To(Equal([]string{"/bin/sh", "-c"}))
+				dir := GinkgoT().TempDir()
+
+				lock.Lock()
+				dirs = append(dirs, dir)
+				lock.Unlock()
+
+				return runLocally(execConfig, "TMPDIR="+dir)
+			}
+
+			var err error
+			out := captureStdout(func() {
+				err = ExecInClusterPods([]havener.Havener{hvnr}, []string{"default/api-*", "--verbose", "foo bar"})
+			})
+
+			Expect(ExitCodeOf(err)).To(Equal(1))
+			Expect(strings.Count(out, "diag.sh --verbose foo bar")).To(Equal(4))
+			Expect(out).To(MatchRegexp(`default/api-0/api\s+7\s`))
+
+			for _, dir := range dirs {
+				Expect(os.ReadDir(dir)).To(BeEmpty())
+			}
+		})
+
+		It("should not use a default command for a script without arguments", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "diag.sh")
+			Expect(os.WriteFile(path, []byte("true\n"), 0644)).To(Succeed())
+			DeferCleanup(SetExecScript(path))
+
+			hvnr.Exec = func(_ havenertest.ExecTarget, execConfig havener.ExecConfig) error {
+				Expect(execConfig.Command[len(execConfig.Command)-1]).To(Equal("diag.sh"))
+				Expect(execConfig.Stdin).ToNot(BeNil())
+				Expect(execConfig.TTY).To(BeFalse())
+				return nil
+			}
+
+			Expect(ExecInClusterPods([]havener.Havener{hvnr}, []string{"dns-0"})).To(Succeed())
+		})
+
+		It("should report a pod name that matches no pod as a missing pod", func() {
+			err := ExecInClusterPods([]havener.Havener{hvnr}, []string{"default/foobar", "true"})
+			Expect(err).To(MatchError(havener.ErrResourceNotFound))
+			Expect(err.Error()).To(ContainSubstring("kube-system/dns-0/dns"))
+
+			var notFoundErr *havener.ResourceNotFoundError
+			Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+			Expect(notFoundErr.Kind).To(Equal(havener.KindPod))
+			Expect(notFoundErr.Name).To(Equal("default/foobar"))
+		})
+
+		It("should list the available pods in case no pod is specified", func() {
+			err := ExecInClusterPods([]havener.Havener{hvnr}, []string{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("kube-system/dns-0/dns"))
+		})
+	})
+})
@@ -22,6 +22,8 @@ package cmd
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -31,6 +33,7 @@ import (
 	"github.com/homeport/havener/pkg/havener"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"k8s.io/client-go/rest"
 
 	"github.com/gonvenience/bunt"
 	"github.com/gonvenience/neat"
@@ -51,7 +54,103 @@ func cause(w wrappedError) string {
 	return w.Unwrap().Error()
 }
 
-var kubeConfig string
+// exitCodeError is used to exit with a specific exit code, for example the
+// exit code of a remote command, without printing an error
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("exit code %d", e.code)
+}
+
+// exitWithCode returns an error to exit with the given exit code, or nil in
+// case the exit code is zero
+func exitWithCode(code int) error {
+	if code == 0 {
+		return nil
+	}
+
+	return &exitCodeError{code: code}
+}
+
+// describeError returns the headline and content for the error box, the
+// typed errors of the havener package result in more specific details and
+// hints how to solve the issue
+func describeError(err error) (headline string, content string) {
+	var (
+		execErr     *havener.ExecError
+		timeoutErr  *havener.HelperPodTimeoutError
+		notFoundErr *havener.ResourceNotFoundError
+	)
+
+	switch {
+	case errors.As(err, &timeoutErr):
+		return fmt.Sprintf("Helper pod %s did not become ready within %v", timeoutErr.Pod, timeoutErr.Timeout),
+			timeoutErr.Description + hint("Check whether the image can be pulled and whether the pod fits on the node, or increase the timeout using --timeout.")
+
+	case errors.As(err, &execErr) && execErr.ExitCode > 0:
+		headline = fmt.Sprintf("Command in pod %s/%s, container %s failed with exit code %d",
+			execErr.Namespace,
+			execErr.Pod,
+			execErr.Container,
+			execErr.ExitCode,
+		)
+
+		if execErr.Node != "" {
+			headline = fmt.Sprintf("Command on node %s failed with exit code %d", execErr.Node, execErr.ExitCode)
+		}
+
+		content = strings.TrimSpace(execErr.Stderr)
+		if content == "" {
+			content = "The command did not write anything to standard error."
+		}
+
+		return headline, content
+	}
+
+	switch err := err.(type) {
+	case wrappedError:
+		headline = title(err)
+		content = cause(err)
+
+	default:
+		headline = "Error occurred"
+		content = err.Error()
+	}
+
+	switch {
+	case errors.Is(err, havener.ErrMetricsAPIUnavailable):
+		content += hint("The usage details are based on the metrics API, which is usually provided by the metrics-server (https://github.com/kubernetes-sigs/metrics-server).")
+
+	case errors.As(err, &notFoundErr):
+		switch notFoundErr.Kind {
+		case havener.KindPod:
+			content += hint("Check the spelling of the name, pods can be specified as [namespace/]pod[/container] including wildcards, or use all to target all pods.")
+
+		case havener.KindNode:
+			content += hint("Check the spelling of the name, or use all to target all nodes.")
+
+		default:
+			content += hint("Check the spelling of the name, use kubectl api-resources to list the available resources.")
+		}
+	}
+
+	return headline, content
+}
+
+func hint(text string) string {
+	return "\n\nHint: " + text
+}
+
+var (
+	kubeConfig   string
+	kubeContext  string
+	kubeContexts []string
+	allContexts  bool
+	inCluster    bool
+	impersonate  rest.ImpersonationConfig
+)
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -85,18 +184,13 @@ func Execute() {
 	}()
 
 	if err := rootCmd.Execute(); err != nil {
-		var headline, content string
-
-		switch err := err.(type) {
-		case wrappedError:
-			headline = title(err)
-			content = cause(err)
-
-		default:
-			headline = "Error occurred"
-			content = err.Error()
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
 		}
 
+		headline, content := describeError(err)
+
 		neat.Box(os.Stderr,
 			headline, strings.NewReader(content),
 			neat.HeadlineColor(bunt.Coral),
@@ -109,15 +203,18 @@ func Execute() {
 }
 
 func init() {
-	kubeConfigDefault, err := havener.KubeConfig()
-	if err != nil {
-		panic(err)
-	}
-
 	rootCmd.Flags().SortFlags = false
 	rootCmd.PersistentFlags().SortFlags = false
 
-	rootCmd.PersistentFlags().StringVar(&kubeConfig, "kubeconfig", kubeConfigDefault, "Kubernetes configuration")
+	rootCmd.PersistentFlags().StringVar(&kubeConfig, "kubeconfig", "", "Kubernetes configuration file (default is to use KUBECONFIG or ~/.kube/config)")
+	rootCmd.PersistentFlags().StringVar(&kubeContext, "context", "", "Kubernetes configuration context to be used (default is the current context)")
+	rootCmd.PersistentFlags().StringSliceVar(&kubeContexts, "contexts", []string{}, "comma separated list of Kubernetes configuration contexts to run the command against concurrently")
+	rootCmd.PersistentFlags().BoolVar(&allContexts, "all-contexts", false, "run the command against all Kubernetes configuration contexts concurrently")
+	rootCmd.PersistentFlags().BoolVar(&inCluster, "in-cluster", false, "use the service account of the pod havener runs in to access the cluster")
+
+	rootCmd.PersistentFlags().StringVar(&impersonate.UserName, "as", "", "username to impersonate for all operations, user can be a regular user or a service account")
+	rootCmd.PersistentFlags().StringArrayVar(&impersonate.Groups, "as-group", []string{}, "group to impersonate for all operations, flag can be repeated to specify multiple groups")
+	rootCmd.PersistentFlags().StringVar(&impersonate.UID, "as-uid", "", "UID to impersonate for all operations")
 
 	rootCmd.PersistentFlags().Int("terminal-width", -1, "disable autodetection and specify an explicit terminal width")
 	rootCmd.PersistentFlags().Int("terminal-height", -1, "disable autodetection and specify an explicit terminal height")
@@ -149,6 +246,70 @@ func init() {
 	}
 }
 
+// newHavener creates a havener handle based on the cluster access settings
+// that were provided using the persistent command-line flags
+func newHavener(ctx context.Context, kubeContext string, extraOpts ...havener.Option) (*havener.Hvnr, error) {
+	var opts = []havener.Option{
+		havener.WithContext(ctx),
+		havener.WithKubeConfigPath(kubeConfig),
+		havener.WithKubeContext(kubeContext),
+		havener.WithImpersonation(impersonate),
+		havener.WithLogger(havenerLogger),
+	}
+
+	opts = append(opts, extraOpts...)
+
+	if inCluster {
+		opts = append(opts, havener.WithInClusterConfig())
+	}
+
+	return havener.NewHavener(opts...)
+}
+
+// newHaveners creates one havener handle per selected cluster, which is more
+// than one in case multiple contexts are selected using --contexts, or
+// --all-contexts is used, the provided options are applied to all handles
+func newHaveners(ctx context.Context, opts ...havener.Option) ([]havener.Havener, error) {
+	var contexts = kubeContexts
+
+	if allContexts {
+		if len(kubeContexts) > 0 {
+			return nil, fmt.Errorf("cannot use --contexts and --all-contexts at the same time")
+		}
+
+		var err error
+		contexts, err = havener.KubeContexts(kubeConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look-up contexts in Kubernetes configuration: %w", err)
+		}
+	}
+
+	switch {
+	case len(contexts) == 0:
+		hvnr, err := newHavener(ctx, kubeContext, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return []havener.Havener{hvnr}, nil
+
+	case kubeContext != "":
+		return nil, fmt.Errorf("cannot use --context in combination with multiple contexts")
+	}
+
+	var result = make([]havener.Havener, 0, len(contexts))
+	for _, name := range contexts {
+		hvnr, err := newHavener(ctx, name, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, hvnr)
+	}
+
+	return result, nil
+}
+
 // exitWithErrorAndIssue leaves the tool with the provided error message and a
 // link that can be used to open a GitHub issue
 func exitWithErrorAndIssue(msg string, err error) {
@@ -176,3 +337,10 @@ func exitWithErrorAndIssue(msg string, err error) {
 func NewHvnrRootCmd() *cobra.Command {
 	return rootCmd
 }
+
+// closeHaveners closes all havener handles, which stops running informers
+func closeHaveners(hvnrs []havener.Havener) {
+	for _, hvnr := range hvnrs {
+		_ = hvnr.Close()
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd_test
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/homeport/havener/internal/cmd"
+
+	"github.com/homeport/havener/pkg/havener"
+)
+
+var _ = Describe("error boxes", func() {
+	It("should use the title and cause of wrapped errors", func() {
+		headline, content := DescribeError(fmt.Errorf("unable to get access to cluster: %w", errors.New("connection refused")))
+		Expect(headline).To(Equal("unable to get access to cluster"))
+		Expect(content).To(Equal("connection refused"))
+	})
+
+	It("should show the standard error output of a failed command", func() {
+		headline, content := DescribeError(fmt.Errorf("pod command execution failed: %w", &havener.ExecError{
+			Namespace: "default",
+			Pod:       "api-0",
+			Container: "api",
+			ExitCode:  127,
+			Stderr:    "sh: foobar: not found\n",
+			Err:       errors.New("command terminated with exit code 127"),
+		}))
+
+		Expect(headline).To(Equal("Command in pod default/api-0, container api failed with exit code 127"))
+		Expect(content).To(Equal("sh: foobar: not found"))
+	})
+
+	It("should name the node of a failed command on a node", func() {
+		headline, content := DescribeError(fmt.Errorf("node command execution failed: %w", &havener.ExecError{
+			Namespace: "kube-system",
+			Pod:       "node-exec-foobar",
+			Container: "node-exec-container",
+			Node:      "node-1",
+			ExitCode:  1,
+			Stderr:    "oops\n",
+			Err:       errors.New("command terminated with exit code 1"),
+		}))
+
+		Expect(headline).To(Equal("Command on node node-1 failed with exit code 1"))
+		Expect(content).To(Equal("oops"))
+	})
+
+	It("should show the describe output of a helper pod that did not become ready", func() {
+		headline, content := DescribeError(&havener.HelperPodTimeoutError{
+			Namespace:   "kube-system",
+			Pod:         "node-exec-foobar",
+			Timeout:     30 * time.Second,
+			Description: "Events: FailedScheduling",
+		})
+
+		Expect(headline).To(Equal("Helper pod node-exec-foobar did not become ready within 30s"))
+		Expect(content).To(HavePrefix("Events: FailedScheduling"))
+		Expect(content).To(ContainSubstring("Hint: "))
+	})
+
+	It("should add a hint that depends on the kind of the missing resource", func() {
+		_, content := DescribeError(fmt.Errorf("no pod matches foo: %w", &havener.ResourceNotFoundError{Kind: havener.KindPod, Name: "foo"}))
+		Expect(content).To(ContainSubstring("[namespace/]pod[/container]"))
+
+		_, content = DescribeError(fmt.Errorf("node 'foo' does not exist: %w", &havener.ResourceNotFoundError{Kind: havener.KindNode, Name: "foo"}))
+		Expect(content).To(ContainSubstring("use all to target all nodes"))
+
+		_, content = DescribeError(&havener.ResourceNotFoundError{Kind: havener.KindCustomResource, Name: "foo"})
+		Expect(content).To(ContainSubstring("kubectl api-resources"))
+	})
+
+	It("should add a hint in case the metrics API is not available", func() {
+		_, content := DescribeError(fmt.Errorf("failed to retrieve usage details from cluster: %w", havener.ErrMetricsAPIUnavailable))
+		Expect(content).To(ContainSubstring("metrics-server"))
+	})
+})
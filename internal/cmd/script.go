@@ -0,0 +1,83 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// scriptRunner is the shell script that extracts the uploaded script from the
+// tar archive on stdin into a temporary directory, runs it with the provided
+// arguments, and removes the temporary directory afterwards
+const scriptRunner = `dir="$(mktemp -d)" || exit 1
+trap 'rm -rf "$dir"' EXIT
+tar -x -f - -C "$dir" || exit 1
+script="$dir/$1"
+shift
+chmod +x "$script" && "$script" "$@" </dev/null`
+
+// script is a local script that is uploaded to each target to run it there
+type script struct {
+	name    string
+	archive []byte
+}
+
+// loadScript reads the local script and packs it into a tar archive, which is
+// streamed to each target
+func loadScript(path string) (*script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script: %w", err)
+	}
+
+	var name = filepath.Base(path)
+	var buf bytes.Buffer
+	var writer = tar.NewWriter(&buf)
+
+	if err := writer.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+		return nil, fmt.Errorf("failed to pack script: %w", err)
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to pack script: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to pack script: %w", err)
+	}
+
+	return &script{name: name, archive: buf.Bytes()}, nil
+}
+
+// command returns the command that runs the script with the given arguments
+func (s *script) command(args []string) []string {
+	return append([]string{"/bin/sh", "-c", scriptRunner, "sh", s.name}, args...)
+}
+
+// stdin returns a new reader for the tar archive that contains the script
+func (s *script) stdin() io.Reader {
+	return bytes.NewReader(s.archive)
+}
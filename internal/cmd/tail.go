@@ -0,0 +1,442 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/gonvenience/bunt"
+	"github.com/homeport/havener/pkg/havener"
+	"github.com/spf13/cobra"
+)
+
+// tailFilesScript follows the files that match the glob patterns, which are
+// passed as arguments. Each pattern is expanded with an empty IFS, so that
+// paths with spaces are not split, patterns without a match are kept as they
+// are. The file headers of tail are used to tell the lines of the files
+// apart, which is why they are always printed.
+const tailFilesScript = `IFS=
+for pattern do
+  shift
+  for file in $pattern; do
+    set -- "$@" "$file"
+  done
+done
+exec tail -v -n 10 -F -- "$@"`
+
+// tailRefreshInterval is the interval in which the list of pods is checked
+// for new pods, in addition to the notifications of the cache
+var tailRefreshInterval = 5 * time.Second
+
+var tailCmdSettings struct {
+	selector      string
+	fieldSelector string
+	since         time.Duration
+	previous      bool
+	include       string
+	exclude       string
+	files         []string
+}
+
+// tailCmd represents the tail command
+var tailCmd = &cobra.Command{
+	Use:   "tail [flags] [[<namespace>/]<pod>[/container]]",
+	Short: "Follow the logs of pod containers",
+	Long: bunt.Sprintf(`*Follow the logs of pod containers*
+
+Streams the logs of all matching pod containers and shows them interleaved,
+with the pod and container name as the origin of each line. The pods are
+selected using the same notation as the _pod-exec_ command, including _all_,
+wildcards, and comma separated lists, for example _havener tail ns/router-*_.
+
+With a label selector (_--selector_) or field selector (_--field-selector_),
+all containers of all matching pods are used, for example
+_havener tail -l app=router_.
+
+Pods that are created while *havener* is running, as well as restarted
+containers, are picked up automatically. Containers that are not running yet
+are followed as soon as they are running, use _--verbose_ to see which
+containers *havener* is waiting for. Use _--since_ to only show recent
+log lines of the pods that are running already, for example _--since 10m_.
+
+With _--previous_, the logs of the previous instance of each container are
+shown instead, for example after it crashed, and *havener* exits afterwards.
+
+With _--files_, the files inside the containers that match the glob pattern
+are followed instead of the container logs, for example
+_havener tail all --files '/var/vcap/sys/log/*/*.log'_, using the file name
+as part of the origin. The containers need to provide _/bin/sh_ and _tail_
+for this. Restarted containers are followed again once they are running.
+
+Log lines can be filtered using regular expressions, _--include_ only shows
+lines that match, and _--exclude_ hides lines that match, for example
+_havener tail all --include 'error|fatal' --exclude healthcheck_.
+`),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		hvnrs, err := newHaveners(cmd.Context(), havener.WithCache())
+		if err != nil {
+			return fmt.Errorf("unable to get access to cluster: %w", err)
+		}
+
+		defer closeHaveners(hvnrs)
+
+		return tailLogs(cmd.Context(), hvnrs, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tailCmd)
+
+	tailCmd.Flags().SortFlags = false
+	tailCmd.Flags().StringVarP(&tailCmdSettings.selector, "selector", "l", "", "label selector of the pods, for example app=router")
+	tailCmd.Flags().StringVar(&tailCmdSettings.fieldSelector, "field-selector", "", "field selector of the pods, for example spec.nodeName=node-0")
+	tailCmd.Flags().DurationVar(&tailCmdSettings.since, "since", 0, "only show log lines newer than the given duration, for example 10m (default is to show all)")
+	tailCmd.Flags().BoolVarP(&tailCmdSettings.previous, "previous", "p", false, "show the logs of the previous container instances and exit")
+	tailCmd.Flags().StringVar(&tailCmdSettings.include, "include", "", "only show log lines that match the regular expression")
+	tailCmd.Flags().StringVar(&tailCmdSettings.exclude, "exclude", "", "hide log lines that match the regular expression")
+	tailCmd.Flags().StringArrayVar(&tailCmdSettings.files, "files", nil, "follow the files inside the containers that match the glob pattern instead of the container logs (can be used multiple times)")
+}
+
+// logFilter filters log lines by regular expressions, an unset expression
+// does not filter anything
+type logFilter struct {
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+}
+
+func newLogFilter(include string, exclude string) (logFilter, error) {
+	var filter logFilter
+	var err error
+
+	if include != "" {
+		if filter.include, err = regexp.Compile(include); err != nil {
+			return filter, fmt.Errorf("invalid include expression: %w", err)
+		}
+	}
+
+	if exclude != "" {
+		if filter.exclude, err = regexp.Compile(exclude); err != nil {
+			return filter, fmt.Errorf("invalid exclude expression: %w", err)
+		}
+	}
+
+	return filter, nil
+}
+
+func (f logFilter) match(line string) bool {
+	if f.include != nil && !f.include.MatchString(line) {
+		return false
+	}
+
+	return f.exclude == nil || !f.exclude.MatchString(line)
+}
+
+// filterOutputMessages forwards the log lines that match the filter, error
+// messages are always forwarded
+func filterOutputMessages(messages chan OutputMsg, filter logFilter) chan OutputMsg {
+	var filtered = make(chan OutputMsg)
+	go func() {
+		defer close(filtered)
+		for msg := range messages {
+			if msg.Stream == "StdErr" || filter.match(msg.Message) {
+				filtered <- msg
+			}
+		}
+	}()
+
+	return filtered
+}
+
+// tailFilesWriter returns a writer for the output of the tail files script,
+// which sends the lines as output messages with the file name as part of the
+// origin. The file headers and the empty lines in front of them are dropped.
+func tailFilesWriter(cluster string, target OutputTarget, c chan OutputMsg) io.WriteCloser {
+	var header = regexp.MustCompile(`^==> (.+) <==$`)
+
+	r, w := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var file string
+		var send = func(line string) {
+			c <- OutputMsg{
+				Timestamp: time.Now(),
+				Stream:    "StdOut",
+				Cluster:   cluster,
+				Origin:    target.Name() + ":" + file,
+				Target:    target,
+				Message:   line,
+			}
+		}
+
+		var pendingEmptyLine bool
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			var line = scanner.Text()
+
+			if matches := header.FindStringSubmatch(line); matches != nil {
+				file, pendingEmptyLine = matches[1], false
+				continue
+			}
+
+			if pendingEmptyLine {
+				send("")
+			}
+
+			if pendingEmptyLine = line == ""; !pendingEmptyLine {
+				send(line)
+			}
+		}
+	}()
+
+	return chanPipeWriter{w, done}
+}
+
+// containerInstance returns the key of the current instance of the container,
+// which changes with each restart, and whether its logs can be streamed
+func containerInstance(pod *corev1.Pod, container string) (string, bool) {
+	var key = fmt.Sprintf("%s/%s/%s/%s", pod.Namespace, pod.Name, container, pod.UID)
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == container {
+			key = fmt.Sprintf("%s/%d", key, status.RestartCount)
+			return key, pod.Status.Phase == corev1.PodRunning && status.State.Waiting == nil
+		}
+	}
+
+	return key, pod.Status.Phase == corev1.PodRunning
+}
+
+// containerState returns a short description of why the container is not
+// running, which is either the reason of its waiting state or the pod phase
+func containerState(pod *corev1.Pod, container string) string {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == container && status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+			return status.State.Waiting.Reason
+		}
+	}
+
+	return string(pod.Status.Phase)
+}
+
+func tailLogs(ctx context.Context, hvnrs []havener.Havener, args []string) error {
+	var input string
+	var filter = havener.ListFilter{
+		LabelSelector: tailCmdSettings.selector,
+		FieldSelector: tailCmdSettings.fieldSelector,
+	}
+
+	if len(tailCmdSettings.files) > 0 && (tailCmdSettings.previous || tailCmdSettings.since > 0) {
+		return fmt.Errorf("cannot use --files in combination with --previous or --since")
+	}
+
+	switch {
+	case len(args) > 1:
+		return fmt.Errorf("more than one pod argument specified, use a comma separated list instead")
+
+	case len(args) == 1:
+		input = args[0]
+
+	case filter.LabelSelector != "" || filter.FieldSelector != "":
+		input = "all"
+
+	default:
+		return availablePodsError(hvnrs, nil, "no pod name specified")
+	}
+
+	logFilter, err := newLogFilter(tailCmdSettings.include, tailCmdSettings.exclude)
+	if err != nil {
+		return err
+	}
+
+	// The streams are stopped in case the command stops because of an error
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		messages = make(chan OutputMsg)
+		printed  = make(chan struct{})
+		streamed = map[string]struct{}{}
+		waiting  = map[string]struct{}{}
+		errs     []error
+		errsMu   sync.Mutex
+	)
+
+	go func() {
+		defer close(printed)
+		PrintOutputMessage(filterOutputMessages(messages, logFilter))
+	}()
+
+	var stream = func(hvnr havener.Havener, pod *corev1.Pod, container string, options havener.ContainerLogsOptions) {
+		defer wg.Done()
+
+		var target = OutputTarget{Namespace: pod.Namespace, Pod: pod.Name, Container: container}
+		var cluster = clusterOf(hvnr, len(hvnrs) > 1)
+
+		options.Context = ctx
+
+		var err error
+		if len(tailCmdSettings.files) > 0 {
+			var stdout = tailFilesWriter(cluster, target, messages)
+			var stderr = chanWriter("StdErr", cluster, target, messages)
+			err = hvnr.PodExec(pod, container, havener.ExecConfig{
+				Command: append([]string{"/bin/sh", "-c", tailFilesScript, "sh"}, tailCmdSettings.files...),
+				Stdout:  stdout,
+				Stderr:  stderr,
+				Context: ctx,
+			})
+
+			_ = stdout.Close()
+			_ = stderr.Close()
+		} else {
+			var stdout = chanWriter("StdOut", cluster, target, messages)
+			err = hvnr.StreamContainerLogs(pod, container, options, stdout)
+			_ = stdout.Close()
+		}
+
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+
+		// Without following, all errors are reported at the end, otherwise
+		// they are shown like log lines to not stop the other streams
+		if !options.Follow {
+			errsMu.Lock()
+			errs = append(errs, err)
+			errsMu.Unlock()
+			return
+		}
+
+		messages <- OutputMsg{
+			Timestamp: time.Now(),
+			Stream:    "StdErr",
+			Cluster:   cluster,
+			Origin:    target.Name(),
+			Target:    target,
+			Message:   err.Error(),
+		}
+	}
+
+	// Starts streams for all matching containers that are not streamed yet
+	// and returns the number of matching containers
+	var update = func(options havener.ContainerLogsOptions) (int, error) {
+		var found int
+		for _, hvnr := range hvnrs {
+			podMap, err := lookupPodsByName(hvnr, input, filter)
+			if err != nil {
+				return found, err
+			}
+
+			for pod, containers := range podMap {
+				for _, container := range containers {
+					found++
+
+					instance, ok := containerInstance(pod, container)
+					var key = hvnr.ClusterName() + "/" + instance
+
+					// Containers that are not running yet are checked again
+					// with the next refresh, which is mentioned only once
+					if !ok && !options.Previous {
+						if _, ok := waiting[key]; !ok && translateLogLevel() >= levelVerbose {
+							log(levelVerbose, fmt.Sprintf("Waiting for container %s of pod %s/%s to be running (%s)",
+								container, pod.Namespace, pod.Name, containerState(pod, container)))
+						}
+
+						waiting[key] = struct{}{}
+						continue
+					}
+
+					if _, ok := streamed[key]; ok {
+						continue
+					}
+
+					streamed[key] = struct{}{}
+					wg.Add(1)
+					go stream(hvnr, pod, container, options)
+				}
+			}
+		}
+
+		return found, nil
+	}
+
+	// Waits for all streams to end and all messages to be printed
+	var shutdown = func() {
+		wg.Wait()
+		close(messages)
+		<-printed
+	}
+
+	// The since setting only applies to the containers that exist already
+	var options = havener.ContainerLogsOptions{
+		Follow:   !tailCmdSettings.previous,
+		Previous: tailCmdSettings.previous,
+		Since:    tailCmdSettings.since,
+	}
+
+	found, err := update(options)
+	if err == nil && found == 0 && input != "all" {
+		err = podNotFoundError(hvnrs, input)
+	}
+
+	if err != nil || tailCmdSettings.previous {
+		if err != nil {
+			cancel()
+		}
+
+		shutdown()
+		return errors.Join(append([]error{err}, errs...)...)
+	}
+
+	// Look for new pods and restarted containers as soon as something
+	// changes, but also on a regular basis
+	var changes = mergeChanges(hvnrs)
+	var ticker = time.NewTicker(tailRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-changes:
+		case <-ctx.Done():
+			shutdown()
+			return nil
+		}
+
+		if _, err := update(havener.ContainerLogsOptions{Follow: true}); err != nil {
+			cancel()
+			shutdown()
+			return err
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package cmd_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gonvenience/bunt"
+	. "github.com/homeport/havener/internal/cmd"
+
+	"github.com/homeport/havener/pkg/havener"
+	"github.com/homeport/havener/pkg/havener/havenertest"
+
+	"github.com/spf13/viper"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("tail", func() {
+	BeforeEach(func() {
+		SetColorSettings(OFF, OFF)
+		DeferCleanup(SetTailSettings(false, "", "", 10*time.Millisecond))
+	})
+
+	AfterEach(func() {
+		SetColorSettings(AUTO, AUTO)
+	})
+
+	// tailFor runs tail until the given duration passed and returns the output
+	var tailFor = func(duration time.Duration, hvnr *havenertest.Havener, ctx context.Context, cancel context.CancelFunc, args ...string) (string, error) {
+		var err error
+		out := captureStdout(func() {
+			time.AfterFunc(duration, cancel)
+			err = TailLogs(ctx, []havener.Havener{hvnr}, args)
+		})
+
+		return out, err
+	}
+
+	It("should follow the logs of all matching pod containers", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		hvnr := havenertest.NewHavenerWithContext(ctx, "fake",
+			examplePod("default", "api-0", "api", "sidecar"),
+			examplePod("default", "db-0", "db"),
+		)
+
+		out, err := tailFor(100*time.Millisecond, hvnr, ctx, cancel, "api-0")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(ContainSubstring("api-0/api │ fake logs"))
+		Expect(out).To(ContainSubstring("api-0/sidecar │ fake logs"))
+		Expect(out).ToNot(ContainSubstring("db-0"))
+
+		var follows int
+		for _, action := range hvnr.Clientset.Actions() {
+			if action.GetSubresource() == "log" {
+				Expect(action.(k8stesting.GenericAction).GetValue()).To(HaveField("Follow", BeTrue()))
+				follows++
+			}
+		}
+
+		Expect(follows).To(Equal(2))
+	})
+
+	It("should pick up pods that are created while running", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		hvnr := havenertest.NewHavenerWithContext(ctx, "fake", examplePod("default", "api-0", "api"))
+
+		time.AfterFunc(50*time.Millisecond, func() {
+			_, _ = hvnr.Client().CoreV1().Pods("default").Create(ctx, examplePod("default", "api-1", "api"), metav1.CreateOptions{})
+		})
+
+		out, err := tailFor(200*time.Millisecond, hvnr, ctx, cancel, "default/api-*")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(ContainSubstring("api-0/api │ fake logs"))
+		Expect(out).To(ContainSubstring("api-1/api │ fake logs"))
+	})
+
+	It("should not stream containers that are not running yet", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		pod := examplePod("default", "api-0", "api")
+		pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}
+		hvnr := havenertest.NewHavenerWithContext(ctx, "fake", pod)
+
+		out, err := tailFor(50*time.Millisecond, hvnr, ctx, cancel, "api-0")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(BeEmpty())
+	})
+
+	It("should mention the containers that are not running yet once in verbose mode", func() {
+		viper.Set("verbose", true)
+		DeferCleanup(viper.Set, "verbose", false)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		pod := examplePod("default", "api-0", "api")
+		pod.Status.ContainerStatuses[0].State.Waiting = &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}
+		hvnr := havenertest.NewHavenerWithContext(ctx, "fake", pod)
+
+		out, err := tailFor(100*time.Millisecond, hvnr, ctx, cancel, "api-0")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(Equal("[INFO] Waiting for container api of pod default/api-0 to be running (ContainerCreating)\n"))
+	})
+
+	It("should only show log lines that match the include and exclude filters", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		hvnr := havenertest.NewHavenerWithContext(ctx, "fake", examplePod("default", "api-0", "api"))
+
+		DeferCleanup(SetTailSettings(false, "logs$", "fake", 10*time.Millisecond))
+		out, err := tailFor(50*time.Millisecond, hvnr, ctx, cancel, "api-0")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(BeEmpty())
+	})
+
+	It("should show the logs of the previous containers and exit", func() {
+		hvnr := havenertest.NewHavener(examplePod("default", "api-0", "api"))
+
+		DeferCleanup(SetTailSettings(true, "", "", time.Hour))
+		out := captureStdout(func() {
+			Expect(TailLogs(context.Background(), []havener.Havener{hvnr}, []string{"api-0"})).To(Succeed())
+		})
+
+		Expect(out).To(ContainSubstring("api-0/api │ fake logs"))
+		for _, action := range hvnr.Clientset.Actions() {
+			if action.GetSubresource() == "log" {
+				Expect(action.(k8stesting.GenericAction).GetValue()).To(HaveField("Previous", BeTrue()))
+			}
+		}
+	})
+
+	Context("following files inside the containers", func() {
+		It("should follow the files matching the glob pattern with the file name as part of the origin", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// The directory name contains a space, which must not split the pattern
+			dir := filepath.Join(GinkgoT().TempDir(), "log files")
+			Expect(os.Mkdir(dir, 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "a.log"), []byte("line a\n"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "b.log"), []byte("line b\n\nafter empty line\n"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "c.txt"), []byte("line c\n"), 0644)).To(Succeed())
+
+			hvnr := havenertest.NewHavenerWithContext(ctx, "fake", examplePod("default", "api-0", "api"))
+			hvnr.Exec = func(_ havenertest.ExecTarget, execConfig havener.ExecConfig) error {
+				cmd := exec.CommandContext(execConfig.Context, execConfig.Command[0], execConfig.Command[1:]...)
+				cmd.Stdout, cmd.Stderr = execConfig.Stdout, execConfig.Stderr
+				return cmd.Run()
+			}
+
+			DeferCleanup(SetTailFiles(filepath.Join(dir, "*.log")))
+			out, err := tailFor(500*time.Millisecond, hvnr, ctx, cancel, "api-0")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(ContainSubstring("api-0/api:" + filepath.Join(dir, "a.log") + " │ line a\n"))
+			Expect(out).To(ContainSubstring("api-0/api:" + filepath.Join(dir, "b.log") + " │ line b\n"))
+			Expect(out).To(ContainSubstring("api-0/api:" + filepath.Join(dir, "b.log") + " │ \n"))
+			Expect(out).To(ContainSubstring("api-0/api:" + filepath.Join(dir, "b.log") + " │ after empty line\n"))
+			Expect(out).ToNot(ContainSubstring("line c"))
+			Expect(out).ToNot(ContainSubstring("==>"))
+		})
+
+		It("should follow the files again once a container was restarted", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			pod := examplePod("default", "api-0", "api")
+			hvnr := havenertest.NewHavenerWithContext(ctx, "fake", pod)
+			hvnr.Exec = havenertest.Reply("==> /var/log/x.log <==\nhello\n", "", 0)
+
+			time.AfterFunc(50*time.Millisecond, func() {
+				restarted := pod.DeepCopy()
+				restarted.Status.ContainerStatuses[0].RestartCount = 1
+				_, _ = hvnr.Client().CoreV1().Pods("default").Update(ctx, restarted, metav1.UpdateOptions{})
+			})
+
+			DeferCleanup(SetTailFiles("/var/log/*.log"))
+			out, err := tailFor(200*time.Millisecond, hvnr, ctx, cancel, "api-0")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(strings.Count(out, "api-0/api:/var/log/x.log │ hello")).To(Equal(2))
+			Expect(hvnr.Executions()).To(Equal([]string{"default/api-0/api", "default/api-0/api"}))
+		})
+
+		It("should fail when used in combination with the previous logs", func() {
+			hvnr := havenertest.NewHavener(examplePod("default", "api-0", "api"))
+
+			DeferCleanup(SetTailSettings(true, "", "", time.Hour))
+			DeferCleanup(SetTailFiles("/var/log/*.log"))
+			Expect(TailLogs(context.Background(), []havener.Havener{hvnr}, []string{"api-0"})).To(MatchError(ContainSubstring("cannot use --files")))
+		})
+	})
+
+	It("should fail with a list of available pods if no pod matches", func() {
+		hvnr := havenertest.NewHavener(examplePod("default", "api-0", "api"))
+
+		err := TailLogs(context.Background(), []havener.Havener{hvnr}, []string{"db-0"})
+		Expect(err).To(MatchError(ContainSubstring("no pod matches db-0")))
+		Expect(err).To(MatchError(ContainSubstring("default/api-0/api")))
+	})
+})
@@ -22,10 +22,12 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"math"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gonvenience/bunt"
@@ -70,16 +72,18 @@ cluster as well as a list per node.
 			topCmdSettings.cycles = 1
 		}
 
-		hvnr, err := havener.NewHavener(havener.WithContext(cmd.Context()), havener.WithKubeConfigPath(kubeConfig))
+		hvnrs, err := newHaveners(cmd.Context(), havener.WithCache())
 		if err != nil {
 			return err
 		}
 
+		defer closeHaveners(hvnrs)
+
 		term.HideCursor()
 		defer term.ShowCursor()
 
 		f := func() error {
-			top, err := hvnr.TopDetails()
+			top, err := retrieveTopDetails(hvnrs)
 			if err != nil {
 				return err
 			}
@@ -135,6 +139,60 @@ func init() {
 	topCmd.PersistentFlags().SortFlags = false
 }
 
+// retrieveTopDetails retrieves the usage details of all given clusters at the
+// same time. In case of multiple clusters, the details are merged into one
+// using the cluster name as a prefix for the node and namespace names.
+func retrieveTopDetails(hvnrs []havener.Havener) (*havener.TopDetails, error) {
+	if len(hvnrs) == 1 {
+		return hvnrs[0].TopDetails()
+	}
+
+	var (
+		wg   sync.WaitGroup
+		tops = make([]*havener.TopDetails, len(hvnrs))
+		errs = make([]error, len(hvnrs))
+	)
+
+	for i := range hvnrs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tops[i], errs[i] = hvnrs[i].TopDetails()
+		}(i)
+	}
+
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	var result = havener.TopDetails{
+		Nodes:      map[string]havener.NodeDetails{},
+		Containers: map[string]map[string]map[string]havener.ContainerDetails{},
+	}
+
+	for i, top := range tops {
+		var prefix = hvnrs[i].ClusterName() + "/"
+
+		for name, details := range top.Nodes {
+			result.Nodes[prefix+name] = details
+		}
+
+		for namespace, podMap := range top.Containers {
+			result.Containers[prefix+namespace] = map[string]map[string]havener.ContainerDetails{}
+			for pod, containerMap := range podMap {
+				result.Containers[prefix+namespace][pod] = map[string]havener.ContainerDetails{}
+				for container, details := range containerMap {
+					details.Nodename = prefix + details.Nodename
+					result.Containers[prefix+namespace][pod][container] = details
+				}
+			}
+		}
+	}
+
+	return &result, nil
+}
+
 // RenderNodeDetails renders a box with usage details per node
 func RenderNodeDetails(topDetails *havener.TopDetails) string {
 	maxNodeNameLength := func() int {
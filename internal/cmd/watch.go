@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gonvenience/bunt"
@@ -36,10 +37,12 @@ import (
 )
 
 var watchCmdSettings struct {
-	interval   int
-	namespaces []string
-	resource   string
-	crd        string
+	interval      int
+	namespaces    []string
+	selector      string
+	fieldSelector string
+	resource      string
+	crd           string
 }
 
 // watchCmd represents the top command
@@ -48,11 +51,13 @@ var watchCmd = &cobra.Command{
 	Short: "Watch status of all pods in all namespaces",
 	Long:  `Continuesly creates a list of all pods in all namespaces.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		hvnr, err := havener.NewHavener(havener.WithContext(cmd.Context()), havener.WithKubeConfigPath(kubeConfig))
+		hvnrs, err := newHaveners(cmd.Context(), havener.WithCache())
 		if err != nil {
 			return err
 		}
 
+		defer closeHaveners(hvnrs)
+
 		if watchCmdSettings.crd != "" && watchCmdSettings.resource != "" {
 			return errors.New("--resource and --crd flags cannot be specified simultaneously")
 		}
@@ -61,18 +66,26 @@ var watchCmd = &cobra.Command{
 		defer term.ShowCursor()
 
 		// Make sure to start with a print
-		if err := printWatchList(hvnr); err != nil {
+		if err := printWatchList(hvnrs); err != nil {
 			return err
 		}
 
+		// Refresh as soon as something changes, but also on a regular basis to
+		// update the age columns
+		var changes = mergeChanges(hvnrs)
 		var ticker = time.NewTicker(time.Duration(watchCmdSettings.interval) * time.Second)
-		for range ticker.C {
-			if err := printWatchList(hvnr); err != nil {
+		for {
+			select {
+			case <-ticker.C:
+			case <-changes:
+			case <-cmd.Context().Done():
+				return nil
+			}
+
+			if err := printWatchList(hvnrs); err != nil {
 				return err
 			}
 		}
-
-		return nil
 	},
 }
 
@@ -81,47 +94,107 @@ func init() {
 	watchCmd.PersistentFlags().IntVarP(&watchCmdSettings.interval, "interval", "i", 2, "interval between measurements in seconds")
 	watchCmd.PersistentFlags().StringVarP(&watchCmdSettings.resource, "resource", "r", "", "resource to watch (default to pods)")
 	watchCmd.PersistentFlags().StringSliceVarP(&watchCmdSettings.namespaces, "namespace", "n", []string{}, "comma separated list of namespaces to filter (default is to use all namespaces")
+	watchCmd.PersistentFlags().StringVarP(&watchCmdSettings.selector, "selector", "l", "", "label selector to filter on, for example app=router")
+	watchCmd.PersistentFlags().StringVar(&watchCmdSettings.fieldSelector, "field-selector", "", "field selector to filter on, for example status.phase=Running")
 	watchCmd.PersistentFlags().StringVarP(&watchCmdSettings.crd, "crd", "c", "", "crd to watch, based on the singular or short-name of the resource")
 }
 
-func printWatchList(hvnr havener.Havener) (err error) {
-	var out string
+// mergeChanges merges the change notifications of all clusters into one
+// channel, where multiple changes in a row result in one notification
+func mergeChanges(hvnrs []havener.Havener) <-chan struct{} {
+	var changes = make(chan struct{}, 1)
+	for _, hvnr := range hvnrs {
+		go func(hvnr havener.Havener) {
+			for {
+				select {
+				case <-hvnr.Changes():
+					select {
+					case changes <- struct{}{}:
+					default:
+					}
+
+				case <-hvnr.Context().Done():
+					return
+				}
+			}
+		}(hvnr)
+	}
+
+	return changes
+}
+
+func printWatchList(hvnrs []havener.Havener) error {
+	var (
+		wg   sync.WaitGroup
+		outs = make([]string, len(hvnrs))
+		errs = make([]error, len(hvnrs))
+		rows = term.GetTerminalHeight()/len(hvnrs) - 3
+	)
+
+	// Render one box per cluster, all clusters share the terminal height
+	for i := range hvnrs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			outs[i], errs[i] = generateWatchTable(hvnrs[i], rows)
+		}(i)
+	}
+
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	print("\x1b[H", "\x1b[2J", strings.Join(outs, "\n"))
+	return nil
+}
 
+func generateWatchTable(hvnr havener.Havener, rows int) (out string, err error) {
 	if watchCmdSettings.crd != "" {
 		out, err = generateCRDTable(hvnr)
 		if err != nil {
-			return err
+			return "", err
 		}
 	} else {
 		switch watchCmdSettings.resource {
 		case "pods":
-			out, err = generatePodsTable(hvnr)
+			out, err = generatePodsTable(hvnr, rows)
 			if err != nil {
-				return err
+				return "", err
 			}
 		case "secrets":
 			out, err = generateSecretsTable(hvnr)
 			if err != nil {
-				return err
+				return "", err
 			}
 		case "configmaps":
 			out, err = generateCMTable(hvnr)
 			if err != nil {
-				return err
+				return "", err
 			}
 		default:
-			out, err = generatePodsTable(hvnr)
+			out, err = generatePodsTable(hvnr, rows)
 			if err != nil {
-				return err
+				return "", err
 			}
 		}
 	}
-	print("\x1b[H", "\x1b[2J", out)
-	return nil
+
+	return out, nil
+}
+
+// watchListFilter returns the filter for the watched resources based on the
+// namespaces and selectors provided with the command-line flags
+func watchListFilter() havener.ListFilter {
+	return havener.ListFilter{
+		Namespaces:    watchCmdSettings.namespaces,
+		LabelSelector: watchCmdSettings.selector,
+		FieldSelector: watchCmdSettings.fieldSelector,
+	}
 }
 
-func generatePodsTable(hvnr havener.Havener) (string, error) {
-	pods, err := hvnr.ListPods(watchCmdSettings.namespaces...)
+func generatePodsTable(hvnr havener.Havener, rows int) (string, error) {
+	pods, err := hvnr.ListPodsWithOptions(watchListFilter())
 	if err != nil {
 		return "", err
 	}
@@ -219,7 +292,7 @@ func generatePodsTable(hvnr havener.Havener) (string, error) {
 		[]string{"Namespace", "Pod", "Ready", "Status", "Node", "Location", "Age"},
 		table,
 		neat.CustomSeparator("  "),
-		neat.LimitRows(term.GetTerminalHeight()-3),
+		neat.LimitRows(rows),
 	)
 
 	if err != nil {
@@ -232,7 +305,7 @@ func generatePodsTable(hvnr havener.Havener) (string, error) {
 func generateSecretsTable(hvnr havener.Havener) (secResult string, err error) {
 	var tableSec = [][]string{}
 
-	secrets, err := hvnr.ListSecrets(watchCmdSettings.namespaces...)
+	secrets, err := hvnr.ListSecretsWithOptions(watchListFilter())
 	if err != nil {
 		return "", err
 	}
@@ -266,7 +339,7 @@ func generateCMTable(hvnr havener.Havener) (cmResult string, err error) {
 
 	var tableSec = [][]string{}
 
-	configMaps, err := hvnr.ListConfigMaps(watchCmdSettings.namespaces...)
+	configMaps, err := hvnr.ListConfigMapsWithOptions(watchListFilter())
 	if err != nil {
 		return "", err
 	}
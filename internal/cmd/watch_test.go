@@ -0,0 +1,62 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/gonvenience/bunt"
+	. "github.com/homeport/havener/internal/cmd"
+
+	"github.com/gonvenience/term"
+	"github.com/homeport/havener/pkg/havener/havenertest"
+)
+
+var _ = Describe("watch", func() {
+	BeforeEach(func() {
+		SetColorSettings(OFF, OFF)
+		term.FixedTerminalWidth = 120
+		term.FixedTerminalHeight = 40
+	})
+
+	AfterEach(func() {
+		SetColorSettings(AUTO, AUTO)
+		term.FixedTerminalWidth = -1
+		term.FixedTerminalHeight = -1
+	})
+
+	It("should render a table with all pods in the cluster", func() {
+		hvnr := havenertest.NewHavener(
+			exampleNamespace("default"),
+			exampleNamespace("kube-system"),
+			exampleNode("node-1"),
+			examplePod("default", "api-0", "api", "sidecar"),
+			examplePod("kube-system", "dns-0", "dns"),
+		)
+
+		out, err := GeneratePodsTable(hvnr, 37)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out).To(ContainSubstring("Pods running in cluster fake"))
+		Expect(out).To(MatchRegexp(`default\s+api-0\s+2/2\s+Running\s+node-1`))
+		Expect(out).To(MatchRegexp(`kube-system\s+dns-0\s+1/1\s+Running\s+node-1`))
+	})
+})
@@ -0,0 +1,77 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package havener_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/homeport/havener/pkg/havener"
+)
+
+var _ = Describe("Archives", func() {
+	It("should extract an archive of a directory using the new name", func() {
+		source := GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(source, "config"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(source, "config", "app.yml"), []byte("port: 8080"), 0644)).To(Succeed())
+
+		var archive bytes.Buffer
+		Expect(CreateArchive(&archive, source, "renamed")).To(Succeed())
+
+		target := GinkgoT().TempDir()
+		Expect(ExtractArchive(&archive, target)).To(Succeed())
+		Expect(os.ReadFile(filepath.Join(target, "renamed", "config", "app.yml"))).To(BeEquivalentTo("port: 8080"))
+	})
+
+	It("should replace the content of an existing file", func() {
+		source := filepath.Join(GinkgoT().TempDir(), "file")
+		Expect(os.WriteFile(source, []byte("new"), 0644)).To(Succeed())
+
+		target := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(target, "file"), []byte("old content"), 0644)).To(Succeed())
+
+		var archive bytes.Buffer
+		Expect(CreateArchive(&archive, source, "file")).To(Succeed())
+		Expect(ExtractArchive(&archive, target)).To(Succeed())
+		Expect(os.ReadFile(filepath.Join(target, "file"))).To(BeEquivalentTo("new"))
+	})
+
+	It("should refuse entries outside of the target directory", func() {
+		var archive bytes.Buffer
+		gzipWriter := gzip.NewWriter(&archive)
+		tarWriter := tar.NewWriter(gzipWriter)
+		Expect(tarWriter.WriteHeader(&tar.Header{Name: "../escape", Mode: 0644, Size: 1, Typeflag: tar.TypeReg})).To(Succeed())
+		_, err := tarWriter.Write([]byte("x"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tarWriter.Close()).To(Succeed())
+		Expect(gzipWriter.Close()).To(Succeed())
+
+		target := filepath.Join(GinkgoT().TempDir(), "target")
+		Expect(ExtractArchive(&archive, target)).To(MatchError(ContainSubstring("outside of the target directory")))
+		Expect(filepath.Join(filepath.Dir(target), "escape")).ToNot(BeAnExistingFile())
+	})
+})
@@ -0,0 +1,158 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package havener
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+// cache is the local cache of cluster resources that is populated and kept
+// current by shared informers, which are started when a resource type is
+// used for the first time
+type cache struct {
+	sync.Mutex
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	logger  *slog.Logger
+	factory informers.SharedInformerFactory
+	started map[string]*informerError
+	changes chan struct{}
+}
+
+// informerError is the latest list and watch error of an informer
+type informerError struct {
+	sync.Mutex
+	err error
+}
+
+func (e *informerError) set(err error) {
+	e.Lock()
+	defer e.Unlock()
+	e.err = err
+}
+
+func (e *informerError) get() error {
+	e.Lock()
+	defer e.Unlock()
+	return e.err
+}
+
+func newCache(ctx context.Context, client kubernetes.Interface, logger *slog.Logger) *cache {
+	ctx, cancel := context.WithCancel(ctx)
+	return &cache{
+		ctx:     ctx,
+		cancel:  cancel,
+		logger:  logger,
+		factory: informers.NewSharedInformerFactory(client, 0),
+		started: map[string]*informerError{},
+		changes: make(chan struct{}, 1),
+	}
+}
+
+// start starts the informer of the given resource type (unless it is already
+// running) and waits until the informer populated the cache. In case the
+// informer is running already, but did not sync yet (for example because of
+// a temporary error), it checks the sync again and fails right away with the
+// latest error, unless the informer recovered in the meantime.
+func (c *cache) start(resource string, informer toolscache.SharedIndexInformer) error {
+	c.Lock()
+	defer c.Unlock()
+
+	failed, ok := c.started[resource]
+	if !ok {
+		var err error
+		if failed, err = c.run(resource, informer); err != nil {
+			return err
+		}
+
+		c.started[resource] = failed
+	}
+
+	return c.sync(resource, informer, failed)
+}
+
+// run starts the informer and returns the latest list and watch error of the
+// informer
+func (c *cache) run(resource string, informer toolscache.SharedIndexInformer) (*informerError, error) {
+	c.logger.Info("Starting informer", "resource", resource)
+
+	// Watch errors are retried by the informer, however the list and watch
+	// error is used to fail early (e.g. missing RBAC permissions) instead of
+	// waiting for a sync that never happens, older errors are replaced
+	var failed = &informerError{}
+	if err := informer.SetWatchErrorHandler(func(_ *toolscache.Reflector, err error) {
+		failed.set(err)
+	}); err != nil {
+		return nil, err
+	}
+
+	if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc:    func(_ interface{}) { c.notify() },
+		UpdateFunc: func(_, _ interface{}) { c.notify() },
+		DeleteFunc: func(_ interface{}) { c.notify() },
+	}); err != nil {
+		return nil, err
+	}
+
+	c.factory.Start(c.ctx.Done())
+	return failed, nil
+}
+
+func (c *cache) sync(resource string, informer toolscache.SharedIndexInformer, failed *informerError) error {
+	for !informer.HasSynced() {
+		if err := failed.get(); err != nil {
+			return fmt.Errorf("failed to populate %s cache: %w", resource, err)
+		}
+
+		select {
+		case <-c.ctx.Done():
+			return fmt.Errorf("failed to populate %s cache: %w", resource, c.ctx.Err())
+
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	return nil
+}
+
+// notify signals that a cached resource changed, multiple changes in a row
+// are merged into one pending signal
+func (c *cache) notify() {
+	select {
+	case c.changes <- struct{}{}:
+	default:
+	}
+}
+
+// stop stops all informers and waits until they are terminated
+func (c *cache) stop() {
+	c.cancel()
+	c.factory.Shutdown()
+}
@@ -22,8 +22,11 @@ package havener
 
 import (
 	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	// https://github.com/kubernetes/client-go/issues/345
@@ -37,13 +40,15 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-
-	"go.yaml.in/yaml/v3"
 )
 
 // KubeConfig returns the path to the Kubernetes configuration,
 // this is either whatever is set in KUBECONFIG,
 // or the well known default location `$HOME/.kube/config`
+//
+// Deprecated: The KUBECONFIG environment variable can contain a list of
+// files, which is why havener uses the client configuration loading rules
+// of kubectl to merge all of them in case no explicit path is configured.
 func KubeConfig() (string, error) {
 	// In case `KUBECONFIG` environment variable is set, this will take precedence
 	if value, ok := os.LookupEnv("KUBECONFIG"); ok {
@@ -59,46 +64,150 @@ func KubeConfig() (string, error) {
 	return filepath.Join(home, ".kube", "config"), nil
 }
 
-// outOfClusterAuthentication for kube authentication from the outside
-func outOfClusterAuthentication(kubeConfig string) (*kubernetes.Clientset, *rest.Config, error) {
-	if kubeConfig == "" {
-		return nil, nil, fmt.Errorf("no kube config supplied")
+// kubeClientConfig returns the client configuration for the given Kubernetes
+// configuration file, optionally overriding the context to be used. Without
+// an explicit file, the same loading rules as with kubectl apply, which means
+// all files listed in KUBECONFIG are merged, or `$HOME/.kube/config` is used.
+func kubeClientConfig(kubeConfig string, kubeContext string) clientcmd.ClientConfig {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeConfig
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: kubeContext},
+	)
+}
+
+// KubeContexts returns the sorted list of names of all contexts defined in the
+// Kubernetes configuration, which is either the explicitly provided file, or
+// the result of the default loading rules in case no file is provided
+func KubeContexts(kubeConfig string) ([]string, error) {
+	cfg, err := kubeClientConfig(kubeConfig, "").RawConfig()
+	if err != nil {
+		return nil, err
 	}
 
-	clusterName, err := clusterName(kubeConfig)
+	result := make([]string, 0, len(cfg.Contexts))
+	for name := range cfg.Contexts {
+		result = append(result, name)
+	}
+
+	sort.Strings(result)
+	return result, nil
+}
+
+// outOfClusterAuthentication for kube authentication from the outside
+func outOfClusterAuthentication(logger *slog.Logger, kubeConfig string, kubeContext string, impersonate rest.ImpersonationConfig) (*kubernetes.Clientset, *rest.Config, error) {
+	clientConfig := kubeClientConfig(kubeConfig, kubeContext)
+
+	clusterName, err := clusterName(clientConfig, kubeContext)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to look-up cluster name: %w", err)
 	}
 
-	logf(Verbose, "Connecting to Kubernetes cluster _%s_ ...", clusterName)
+	logger.Info("Connecting to Kubernetes cluster", "cluster", clusterName)
 
-	// BuildConfigFromFlags is a helper function that builds configs from a master
-	// url or a kubeconfig filepath.
-	config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
+	// ClientConfig builds the REST config based on the Kubernetes configuration
+	// and the context (either the current context or the explicitly set one)
+	config, err := clientConfig.ClientConfig()
 	if err != nil {
 		return nil, nil, err
 	}
 
+	impersonateAs(logger, config, impersonate)
+
 	// create the clientset
 	clientset, err := kubernetes.NewForConfig(config)
 
-	logf(Verbose, "Successfully connected to Kubernetes cluster.")
+	logger.Info("Successfully connected to Kubernetes cluster", "cluster", clusterName)
 	return clientset, config, err
 }
 
-func clusterName(kubeConfig string) (string, error) {
-	data, err := os.ReadFile(kubeConfig)
+// inClusterAuthentication for kube authentication from inside a pod, based on
+// the service account token that is mounted into the pod
+func inClusterAuthentication(logger *slog.Logger, impersonate rest.ImpersonationConfig) (*kubernetes.Clientset, *rest.Config, error) {
+	logger.Info("Connecting to Kubernetes cluster using in-cluster configuration")
+
+	config, err := rest.InClusterConfig()
 	if err != nil {
-		return "", err
+		return nil, nil, err
+	}
+
+	impersonateAs(logger, config, impersonate)
+
+	// create the clientset
+	clientset, err := kubernetes.NewForConfig(config)
+
+	logger.Info("Successfully connected to Kubernetes cluster")
+	return clientset, config, err
+}
+
+// impersonateAs configures the REST config to act as another user, in case an
+// user to impersonate is set, so that all requests use the identity of it
+func impersonateAs(logger *slog.Logger, config *rest.Config, impersonate rest.ImpersonationConfig) {
+	if impersonate.UserName == "" {
+		return
 	}
 
-	var cfg map[string]interface{}
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	logger.Info("Impersonating user", "user", impersonate.UserName)
+	config.Impersonate = impersonate
+}
+
+// isRunningInCluster checks whether the environment variables of the service
+// that Kubernetes sets up for pods are available
+func isRunningInCluster() bool {
+	return os.Getenv("KUBERNETES_SERVICE_HOST") != "" && os.Getenv("KUBERNETES_SERVICE_PORT") != ""
+}
+
+// kubeConfigAvailable checks whether at least one of the Kubernetes
+// configuration files of the default loading rules exists
+func kubeConfigAvailable() bool {
+	for _, path := range clientcmd.NewDefaultClientConfigLoadingRules().GetLoadingPrecedence() {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inClusterName returns the cluster name to be used with the in-cluster
+// configuration, which is either what is set in HAVENER_CLUSTER_NAME, or
+// the host name of the API server
+func inClusterName(config *rest.Config) string {
+	if name, ok := os.LookupEnv("HAVENER_CLUSTER_NAME"); ok && name != "" {
+		return name
+	}
+
+	return apiServerHostName(config)
+}
+
+// apiServerHostName returns the host name of the API server that is
+// configured in the REST config
+func apiServerHostName(config *rest.Config) string {
+	if u, err := url.Parse(config.Host); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+
+	return config.Host
+}
+
+func clusterName(clientConfig clientcmd.ClientConfig, kubeContext string) (string, error) {
+	cfg, err := clientConfig.RawConfig()
+	if err != nil {
 		return "", err
 	}
 
-	if name, ok := cfg["current-context"]; ok {
-		return fmt.Sprintf("%v", name), nil
+	if kubeContext != "" {
+		if _, ok := cfg.Contexts[kubeContext]; !ok {
+			return "", fmt.Errorf("context %s does not exist in Kubernetes configuration", kubeContext)
+		}
+
+		return kubeContext, nil
+	}
+
+	if cfg.CurrentContext != "" {
+		return cfg.CurrentContext, nil
 	}
 
 	return "", fmt.Errorf("unable to determine cluster name based on Kubernetes configuration")
@@ -0,0 +1,143 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package havener
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrResourceNotFound is the error of a resource that does not exist
+	ErrResourceNotFound = errors.New("resource not found")
+
+	// ErrMetricsAPIUnavailable is the error of a cluster without metrics API,
+	// which is usually the case if the metrics server is not installed
+	ErrMetricsAPIUnavailable = errors.New("metrics API is not available")
+
+	// ErrHelperPodTimeout is the error of a helper pod that did not become
+	// ready in time, see HelperPodTimeoutError for the details
+	ErrHelperPodTimeout = errors.New("helper pod did not become ready in time")
+)
+
+// Kinds of resources used in ResourceNotFoundError
+const (
+	KindPod            = "pod"
+	KindNode           = "node"
+	KindCustomResource = "custom resource"
+)
+
+// ResourceNotFoundError is the error of a resource that does not exist in
+// the cluster, it matches ErrResourceNotFound
+type ResourceNotFoundError struct {
+	Kind string
+	Name string
+}
+
+func (e *ResourceNotFoundError) Error() string {
+	return fmt.Sprintf("desired %s %s, was not found", e.Kind, e.Name)
+}
+
+// Is reports whether the target is ErrResourceNotFound
+func (e *ResourceNotFoundError) Is(target error) bool {
+	return target == ErrResourceNotFound
+}
+
+// ExecError is the error of a command execution in a pod container or on a
+// node that failed, either because the command terminated with a non-zero exit
+// code, or because the command could not be executed at all
+type ExecError struct {
+	Namespace string
+	Pod       string
+	Container string
+
+	// Node is the name of the node for command executions on a node, where the
+	// namespace, pod, and container are the ones of the temporary helper pod
+	Node string
+
+	// ExitCode is the exit code of the command, it is -1 in case the command
+	// did not terminate by itself (for example due to connection issues)
+	ExitCode int
+
+	// Stderr is the end of the standard error output of the command (if the
+	// standard error stream was used)
+	Stderr string
+
+	Err error
+}
+
+func (e *ExecError) Error() string {
+	if e.Node != "" {
+		return fmt.Sprintf("failed to execute command on node %s: %v", e.Node, e.Err)
+	}
+
+	return fmt.Sprintf("failed to execute command on pod %s, container %s: %v", e.Pod, e.Container, e.Err)
+}
+
+func (e *ExecError) Unwrap() error {
+	return e.Err
+}
+
+// HelperPodTimeoutError is the error of a helper pod that did not become ready
+// within the timeout, it matches ErrHelperPodTimeout
+type HelperPodTimeoutError struct {
+	Namespace string
+	Pod       string
+	Timeout   time.Duration
+
+	// Description is the describe output of the pod at the moment of the
+	// timeout, which usually includes the reason why it is not ready
+	Description string
+}
+
+func (e *HelperPodTimeoutError) Error() string {
+	return fmt.Sprintf("Giving up waiting for pod %s in namespace %s to become ready within %v: status of pod at the moment of the timeout:\n\n%s",
+		e.Pod,
+		e.Namespace,
+		e.Timeout,
+		e.Description,
+	)
+}
+
+// Is reports whether the target is ErrHelperPodTimeout
+func (e *HelperPodTimeoutError) Is(target error) bool {
+	return target == ErrHelperPodTimeout
+}
+
+// tailBuffer is a writer that only keeps the last bytes written to it
+type tailBuffer struct {
+	limit int
+	data  []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.data = append(b.data, p...)
+	if len(b.data) > b.limit {
+		b.data = b.data[len(b.data)-b.limit:]
+	}
+
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	return string(b.data)
+}
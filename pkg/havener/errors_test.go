@@ -0,0 +1,92 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package havener_test
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/homeport/havener/pkg/havener"
+
+	"github.com/homeport/havener/pkg/havener/havenertest"
+	"k8s.io/client-go/util/exec"
+)
+
+var _ = Describe("Errors", func() {
+	It("should report a missing custom resource definition as resource not found", func() {
+		_, err := havenertest.NewHavener().ListCustomResourceDefinition("foobar")
+		Expect(err).To(MatchError(ErrResourceNotFound))
+
+		var notFoundErr *ResourceNotFoundError
+		Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+		Expect(notFoundErr.Name).To(Equal("foobar"))
+		Expect(notFoundErr.Kind).To(Equal(KindCustomResource))
+	})
+
+	It("should match the helper pod timeout sentinel and keep the describe output", func() {
+		var err error = fmt.Errorf("failed to run on node: %w", &HelperPodTimeoutError{
+			Namespace:   "kube-system",
+			Pod:         "node-exec-foobar",
+			Timeout:     5 * time.Second,
+			Description: "Events: FailedScheduling",
+		})
+
+		Expect(err).To(MatchError(ErrHelperPodTimeout))
+
+		var timeoutErr *HelperPodTimeoutError
+		Expect(errors.As(err, &timeoutErr)).To(BeTrue())
+		Expect(timeoutErr.Description).To(Equal("Events: FailedScheduling"))
+		Expect(err.Error()).To(ContainSubstring("status of pod at the moment of the timeout"))
+	})
+
+	It("should provide access to the underlying error of a command execution", func() {
+		var err error = &ExecError{
+			Namespace: "default",
+			Pod:       "api-0",
+			Container: "api",
+			ExitCode:  2,
+			Err:       exec.CodeExitError{Err: errors.New("command terminated with exit code 2"), Code: 2},
+		}
+
+		Expect(err.Error()).To(Equal("failed to execute command on pod api-0, container api: command terminated with exit code 2"))
+
+		var exitErr exec.CodeExitError
+		Expect(errors.As(err, &exitErr)).To(BeTrue())
+		Expect(exitErr.Code).To(Equal(2))
+	})
+
+	It("should name the node instead of the helper pod of a command execution on a node", func() {
+		var err error = &ExecError{
+			Namespace: "kube-system",
+			Pod:       "node-exec-foobar",
+			Container: "node-exec-container",
+			Node:      "node-1",
+			ExitCode:  2,
+			Err:       exec.CodeExitError{Err: errors.New("command terminated with exit code 2"), Code: 2},
+		}
+
+		Expect(err.Error()).To(Equal("failed to execute command on node node-1: command terminated with exit code 2"))
+	})
+})
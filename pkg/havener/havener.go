@@ -32,6 +32,8 @@ package havener
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"strconv"
 	"time"
@@ -85,9 +87,15 @@ func GracefulShutdown() {
 type Hvnr struct {
 	ctx            context.Context
 	kubeConfigPath string
+	kubeContext    string
+	inCluster      bool
+	impersonate    rest.ImpersonationConfig
+	withCache      bool
 	client         kubernetes.Interface
 	restconfig     *rest.Config
 	clusterName    string
+	cache          *cache
+	logger         *slog.Logger
 }
 
 // Havener is an interface to work with a cluster through the havener
@@ -100,25 +108,91 @@ type Havener interface {
 
 	ListNamespaces() ([]string, error)
 	ListPods(namespaces ...string) ([]*corev1.Pod, error)
+	ListPodsWithOptions(filter ListFilter) ([]*corev1.Pod, error)
 	ListNodes() ([]corev1.Node, error)
+	ListNodesWithOptions(filter ListFilter) ([]corev1.Node, error)
 	ListSecrets(namespaces ...string) ([]*corev1.Secret, error)
+	ListSecretsWithOptions(filter ListFilter) ([]*corev1.Secret, error)
 	ListConfigMaps(namespaces ...string) ([]*corev1.ConfigMap, error)
+	ListConfigMapsWithOptions(filter ListFilter) ([]*corev1.ConfigMap, error)
+	ListEvents(namespaces ...string) ([]*corev1.Event, error)
 	ListCustomResourceDefinition(string) ([]unstructured.Unstructured, error)
 
 	TopDetails() (*TopDetails, error)
 	RetrieveLogs(parallelDownloads int, target string, includeConfigFiles bool) error
+	RetrieveLogsWithOptions(options RetrieveLogsOptions) error
+	StreamContainerLogs(pod *corev1.Pod, container string, options ContainerLogsOptions, w io.Writer) error
 
 	PodExec(pod *corev1.Pod, container string, execConfig ExecConfig) error
+	PodExecWithResult(pod *corev1.Pod, container string, execConfig ExecConfig) (ExecResult, error)
 	NodeExec(node corev1.Node, hlpPodConfig NodeExecHelperPodConfig, execConfig ExecConfig) error
+	NodeExecWithResult(node corev1.Node, hlpPodConfig NodeExecHelperPodConfig, execConfig ExecConfig) (ExecResult, error)
+
+	Changes() <-chan struct{}
+	Close() error
 }
 
 // Option provides a way to set specific settings for creating the Havener setup
 type Option func(*Hvnr)
 
+// WithKubeConfigPath is an option to use an explicit Kubernetes configuration
+// file instead of the files listed in KUBECONFIG (or the default location)
 func WithKubeConfigPath(kubeConfig string) Option {
 	return func(h *Hvnr) { h.kubeConfigPath = kubeConfig }
 }
 
+// WithKubeContext is an option to use a specific context of the Kubernetes
+// configuration instead of its current context
+func WithKubeContext(kubeContext string) Option {
+	return func(h *Hvnr) { h.kubeContext = kubeContext }
+}
+
+// WithInClusterConfig is an option to access the cluster from inside a pod
+// using the service account of the pod. It is used automatically, if there
+// is no Kubernetes configuration available, but havener runs inside a pod.
+func WithInClusterConfig() Option {
+	return func(h *Hvnr) { h.inCluster = true }
+}
+
+// WithClient is an option to use the provided Kubernetes client and REST
+// configuration instead of setting up the cluster access, which is useful
+// for example to use a fake clientset in unit tests
+func WithClient(client kubernetes.Interface, restconfig *rest.Config) Option {
+	return func(h *Hvnr) {
+		h.client = client
+		h.restconfig = restconfig
+	}
+}
+
+// WithClusterName is an option to explicitly set the name of the cluster
+// instead of using the name of the context of the Kubernetes configuration
+func WithClusterName(clusterName string) Option {
+	return func(h *Hvnr) { h.clusterName = clusterName }
+}
+
+// WithImpersonation is an option to act as another user (and groups) for all
+// requests against the cluster
+func WithImpersonation(impersonate rest.ImpersonationConfig) Option {
+	return func(h *Hvnr) { h.impersonate = impersonate }
+}
+
+// WithCache is an option to serve the pods, nodes, secrets, configmaps, and
+// events from a local cache, which is kept current by shared informers. The
+// informer of a resource type is started when it is listed the first time.
+// As long as an informer cannot populate the cache, for example because the
+// resources cannot be listed in all namespaces, list calls are used instead.
+// Use Close to stop the informers.
+func WithCache() Option {
+	return func(h *Hvnr) { h.withCache = true }
+}
+
+// WithLogger is an option to set the logger for the log messages of havener,
+// which contain structured fields like the cluster, namespace, pod, or
+// container. By default, all log messages are discarded.
+func WithLogger(logger *slog.Logger) Option {
+	return func(h *Hvnr) { h.logger = logger }
+}
+
 // WithContext is an option to set the context
 func WithContext(ctx context.Context) Option {
 	return func(h *Hvnr) { h.ctx = ctx }
@@ -136,25 +210,76 @@ func NewHavener(opts ...Option) (hvnr *Hvnr, err error) {
 		hvnr.ctx = context.Background()
 	}
 
-	// In case there is no Kubernetes configuration set, use the default
-	if hvnr.kubeConfigPath == "" {
-		hvnr.kubeConfigPath, err = KubeConfig()
+	// Discard log messages if no logger is set, see GetLogChannel
+	if hvnr.logger == nil {
+		hvnr.logger = defaultLogger()
+	}
+
+	if hvnr.impersonate.UserName == "" && (hvnr.impersonate.UID != "" || len(hvnr.impersonate.Groups) > 0 || len(hvnr.impersonate.Extra) > 0) {
+		return nil, fmt.Errorf("impersonating a UID, groups, or extra fields requires impersonating a user")
+	}
+
+	// With a client being provided, there is no need to set up cluster access
+	if hvnr.client == nil {
+		if err = hvnr.setupClusterAccess(); err != nil {
+			return nil, err
+		}
+	}
+
+	if hvnr.restconfig == nil {
+		hvnr.restconfig = &rest.Config{}
+	}
+
+	if hvnr.clusterName == "" {
+		hvnr.clusterName = apiServerHostName(hvnr.restconfig)
+	}
+
+	hvnr.logger = hvnr.logger.With("cluster", hvnr.clusterName)
+
+	if hvnr.withCache {
+		hvnr.cache = newCache(hvnr.ctx, hvnr.client, hvnr.logger)
+	}
+
+	return hvnr, nil
+}
+
+func (h *Hvnr) setupClusterAccess() (err error) {
+	// Without any Kubernetes configuration, but running inside a pod, default
+	// to use the service account of the pod to access the cluster
+	if !h.inCluster && h.kubeConfigPath == "" && h.kubeContext == "" {
+		h.inCluster = !kubeConfigAvailable() && isRunningInCluster()
+	}
+
+	if h.inCluster {
+		if h.kubeConfigPath != "" || h.kubeContext != "" {
+			return fmt.Errorf("in-cluster configuration cannot be used in combination with a Kubernetes configuration or context")
+		}
+
+		h.client, h.restconfig, err = inClusterAuthentication(h.logger, h.impersonate)
 		if err != nil {
-			return nil, fmt.Errorf("failed to look-up default kube config: %w", err)
+			return fmt.Errorf("unable to get access to cluster: %w", err)
+		}
+
+		if h.clusterName == "" {
+			h.clusterName = inClusterName(h.restconfig)
 		}
+
+		return nil
 	}
 
-	hvnr.client, hvnr.restconfig, err = outOfClusterAuthentication(hvnr.kubeConfigPath)
+	h.client, h.restconfig, err = outOfClusterAuthentication(h.logger, h.kubeConfigPath, h.kubeContext, h.impersonate)
 	if err != nil {
-		return nil, fmt.Errorf("unable to get access to cluster: %w", err)
+		return fmt.Errorf("unable to get access to cluster: %w", err)
 	}
 
-	hvnr.clusterName, err = clusterName(hvnr.kubeConfigPath)
-	if err != nil {
-		return nil, fmt.Errorf("unable to get cluster name: %w", err)
+	if h.clusterName == "" {
+		h.clusterName, err = clusterName(kubeClientConfig(h.kubeConfigPath, h.kubeContext), h.kubeContext)
+		if err != nil {
+			return fmt.Errorf("unable to get cluster name: %w", err)
+		}
 	}
 
-	return hvnr, nil
+	return nil
 }
 
 // Context returns the context of the Havener handle
@@ -175,6 +300,25 @@ func (h *Hvnr) RESTConfig() *rest.Config {
 	return h.restconfig
 }
 
+// Changes returns a channel that signals changes of cached resources, which
+// is nil (blocks forever) in case the cache is not used
+func (h *Hvnr) Changes() <-chan struct{} {
+	if h.cache == nil {
+		return nil
+	}
+
+	return h.cache.changes
+}
+
+// Close stops the informers of the cache, if it is used
+func (h *Hvnr) Close() error {
+	if h.cache != nil {
+		h.cache.stop()
+	}
+
+	return nil
+}
+
 func (h *Hvnr) describePod(pod *corev1.Pod) (string, error) {
 	describer, ok := describe.DescriberFor(schema.GroupKind{Group: corev1.GroupName, Kind: "Pod"}, h.restconfig)
 	if !ok {
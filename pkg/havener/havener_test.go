@@ -0,0 +1,232 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package havener_test
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/homeport/havener/pkg/havener"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+const exampleKubeConfig = `---
+apiVersion: v1
+kind: Config
+clusters:
+- name: one
+  cluster:
+    server: https://one.example.com
+- name: two
+  cluster:
+    server: https://two.example.com
+contexts:
+- name: one
+  context:
+    cluster: one
+    user: admin
+- name: two
+  context:
+    cluster: two
+    user: admin
+current-context: one
+users:
+- name: admin
+  user:
+    token: foobar
+`
+
+const otherKubeConfig = `---
+apiVersion: v1
+kind: Config
+clusters:
+- name: three
+  cluster:
+    server: https://three.example.com
+contexts:
+- name: three
+  context:
+    cluster: three
+    user: admin
+current-context: three
+users:
+- name: admin
+  user:
+    token: foobar
+`
+
+var _ = Describe("Havener setup", func() {
+	var kubeConfigPath string
+
+	BeforeEach(func() {
+		kubeConfigPath = filepath.Join(GinkgoT().TempDir(), "config")
+		Expect(os.WriteFile(kubeConfigPath, []byte(exampleKubeConfig), 0600)).To(Succeed())
+	})
+
+	Context("selecting the Kubernetes configuration context", func() {
+		It("should use the current context by default", func() {
+			hvnr, err := NewHavener(WithKubeConfigPath(kubeConfigPath))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hvnr.ClusterName()).To(Equal("one"))
+			Expect(hvnr.RESTConfig().Host).To(Equal("https://one.example.com"))
+		})
+
+		It("should use the explicitly configured context", func() {
+			hvnr, err := NewHavener(WithKubeConfigPath(kubeConfigPath), WithKubeContext("two"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hvnr.ClusterName()).To(Equal("two"))
+			Expect(hvnr.RESTConfig().Host).To(Equal("https://two.example.com"))
+		})
+
+		It("should fail if the configured context does not exist", func() {
+			_, err := NewHavener(WithKubeConfigPath(kubeConfigPath), WithKubeContext("three"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("impersonating another user", func() {
+		It("should configure the REST config to impersonate the user and groups", func() {
+			hvnr, err := NewHavener(
+				WithKubeConfigPath(kubeConfigPath),
+				WithImpersonation(rest.ImpersonationConfig{UserName: "system:serviceaccount:tenant:viewer", Groups: []string{"tenants"}}),
+			)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hvnr.RESTConfig().Impersonate.UserName).To(Equal("system:serviceaccount:tenant:viewer"))
+			Expect(hvnr.RESTConfig().Impersonate.Groups).To(Equal([]string{"tenants"}))
+		})
+
+		It("should fail to impersonate groups without a user", func() {
+			_, err := NewHavener(
+				WithKubeConfigPath(kubeConfigPath),
+				WithImpersonation(rest.ImpersonationConfig{Groups: []string{"tenants"}}),
+			)
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("loading multiple Kubernetes configuration files", func() {
+		var otherKubeConfigPath string
+
+		BeforeEach(func() {
+			otherKubeConfigPath = filepath.Join(GinkgoT().TempDir(), "other")
+			Expect(os.WriteFile(otherKubeConfigPath, []byte(otherKubeConfig), 0600)).To(Succeed())
+
+			GinkgoT().Setenv("KUBECONFIG", strings.Join([]string{otherKubeConfigPath, kubeConfigPath}, string(filepath.ListSeparator)))
+		})
+
+		It("should merge all files listed in KUBECONFIG", func() {
+			hvnr, err := NewHavener()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hvnr.ClusterName()).To(Equal("three"))
+			Expect(hvnr.RESTConfig().Host).To(Equal("https://three.example.com"))
+
+			hvnr, err = NewHavener(WithKubeContext("two"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hvnr.ClusterName()).To(Equal("two"))
+			Expect(hvnr.RESTConfig().Host).To(Equal("https://two.example.com"))
+		})
+
+		It("should list the contexts of all files listed in KUBECONFIG", func() {
+			Expect(KubeContexts("")).To(Equal([]string{"one", "three", "two"}))
+			Expect(KubeContexts(kubeConfigPath)).To(Equal([]string{"one", "two"}))
+		})
+
+		It("should only use the explicit configuration file if one is set", func() {
+			hvnr, err := NewHavener(WithKubeConfigPath(kubeConfigPath))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(hvnr.ClusterName()).To(Equal("one"))
+
+			_, err = NewHavener(WithKubeConfigPath(kubeConfigPath), WithKubeContext("three"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("using the in-cluster configuration", func() {
+		It("should fail when it is not running inside a pod", func() {
+			GinkgoT().Setenv("KUBERNETES_SERVICE_HOST", "")
+			GinkgoT().Setenv("KUBERNETES_SERVICE_PORT", "")
+
+			_, err := NewHavener(WithInClusterConfig())
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail when used together with a Kubernetes configuration", func() {
+			_, err := NewHavener(WithInClusterConfig(), WithKubeConfigPath(kubeConfigPath))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Logging", func() {
+	It("should not block when no logger is set", func() {
+		hvnr, err := NewHavener(WithClient(fake.NewSimpleClientset(), nil))
+		Expect(err).ToNot(HaveOccurred())
+
+		for i := 0; i < 250; i++ {
+			_, err := hvnr.ListPods()
+			Expect(err).ToNot(HaveOccurred())
+		}
+	})
+
+	It("should use the provided logger with structured fields", func() {
+		var buf bytes.Buffer
+		hvnr, err := NewHavener(
+			WithClient(fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api-0"}}), nil),
+			WithClusterName("foobar"),
+			WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = hvnr.ListPodsWithOptions(ListFilter{Namespaces: []string{"default"}, LabelSelector: "app=api"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(buf.String()).To(ContainSubstring(`msg="Listing pods" cluster=foobar filter.namespaces=default filter.labelSelector="app=api"`))
+	})
+
+	It("should send the log messages to the deprecated log channel once it is used", func() {
+		logChannel := GetLogChannel()
+		for len(logChannel) > 0 {
+			<-logChannel
+		}
+
+		hvnr, err := NewHavener(WithClient(fake.NewSimpleClientset(), nil), WithClusterName("foobar"))
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = hvnr.ListPods()
+		Expect(err).ToNot(HaveOccurred())
+
+		var msg LogMessage
+		Eventually(logChannel).Should(Receive(&msg))
+		Expect(msg.Level).To(Equal(Verbose))
+		Expect(msg.Message).To(HavePrefix("Listing pods cluster=foobar"))
+	})
+})
@@ -0,0 +1,210 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+/*
+Package havenertest provides a fake Havener implementation to unit-test code
+that is built on top of the havener package.
+
+The fake is backed by the fake clientset of client-go, so that all functions
+working with the Kubernetes API (for example listing pods) work on the objects
+the fake was set up with. Since there is no real cluster to execute commands
+in, the pod and node command execution is handled by a pluggable exec stub.
+*/
+package havenertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/homeport/havener/pkg/havener"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/exec"
+)
+
+// DefaultClusterName is the cluster name of a fake Havener
+const DefaultClusterName = "fake"
+
+// ExecTarget describes where a command is supposed to be executed, which is
+// either a container of a pod, or a node
+type ExecTarget struct {
+	Pod       *corev1.Pod
+	Container string
+	Node      *corev1.Node
+}
+
+// String returns the target in the same notation as used by havener
+func (t ExecTarget) String() string {
+	if t.Node != nil {
+		return t.Node.Name
+	}
+
+	return fmt.Sprintf("%s/%s/%s", t.Pod.Namespace, t.Pod.Name, t.Container)
+}
+
+// ExecFunc is the stub that is called instead of executing a command in a pod
+// container or on a node, errors are wrapped into a havener.ExecError
+type ExecFunc func(target ExecTarget, execConfig havener.ExecConfig) error
+
+// Reply returns an exec stub that writes the provided output to the streams
+// and fails with the same error client-go returns in case of a non-zero exit
+// code of the command
+func Reply(stdout string, stderr string, exitCode int) ExecFunc {
+	return func(_ ExecTarget, execConfig havener.ExecConfig) error {
+		if execConfig.Stdout != nil {
+			if _, err := io.WriteString(execConfig.Stdout, stdout); err != nil {
+				return err
+			}
+		}
+
+		if execConfig.Stderr != nil {
+			if _, err := io.WriteString(execConfig.Stderr, stderr); err != nil {
+				return err
+			}
+		}
+
+		if exitCode != 0 {
+			return exec.CodeExitError{
+				Err:  fmt.Errorf("command terminated with exit code %d", exitCode),
+				Code: exitCode,
+			}
+		}
+
+		return nil
+	}
+}
+
+// Havener is a fake Havener, which uses a fake clientset and an exec stub
+type Havener struct {
+	*havener.Hvnr
+
+	// Clientset is the fake clientset used by the fake Havener, which can be
+	// used to add reactors or to check the recorded actions
+	Clientset *fake.Clientset
+
+	// Exec is the stub that is called for command executions, in case it is
+	// not set, commands succeed without any output
+	Exec ExecFunc
+
+	mu         sync.Mutex
+	executions []ExecTarget
+}
+
+var _ havener.Havener = &Havener{}
+
+// NewHavener returns a new fake Havener with a fake clientset, that contains
+// the provided objects
+func NewHavener(objects ...runtime.Object) *Havener {
+	return NewHavenerWithContext(context.Background(), DefaultClusterName, objects...)
+}
+
+// NewHavenerWithContext returns a new fake Havener with the given context and
+// cluster name and a fake clientset, that contains the provided objects
+func NewHavenerWithContext(ctx context.Context, clusterName string, objects ...runtime.Object) *Havener {
+	clientset := fake.NewSimpleClientset(objects...)
+
+	hvnr, err := havener.NewHavener(
+		havener.WithContext(ctx),
+		havener.WithClient(clientset, &rest.Config{Host: "https://" + clusterName}),
+		havener.WithClusterName(clusterName),
+	)
+
+	// Setting up a Havener with a provided client cannot fail
+	if err != nil {
+		panic(err)
+	}
+
+	return &Havener{
+		Hvnr:      hvnr,
+		Clientset: clientset,
+	}
+}
+
+// PodExec calls the exec stub with the pod container as the target
+func (h *Havener) PodExec(pod *corev1.Pod, container string, execConfig havener.ExecConfig) error {
+	_, err := h.exec(ExecTarget{Pod: pod, Container: container}, execConfig)
+	return err
+}
+
+// PodExecWithResult calls the exec stub with the pod container as the target
+// and returns the result of it
+func (h *Havener) PodExecWithResult(pod *corev1.Pod, container string, execConfig havener.ExecConfig) (havener.ExecResult, error) {
+	return havener.ExitCodeResult(h.exec(ExecTarget{Pod: pod, Container: container}, execConfig))
+}
+
+// NodeExec calls the exec stub with the node as the target
+func (h *Havener) NodeExec(node corev1.Node, _ havener.NodeExecHelperPodConfig, execConfig havener.ExecConfig) error {
+	_, err := h.exec(ExecTarget{Node: &node}, execConfig)
+	return err
+}
+
+// NodeExecWithResult calls the exec stub with the node as the target and
+// returns the result of it
+func (h *Havener) NodeExecWithResult(node corev1.Node, _ havener.NodeExecHelperPodConfig, execConfig havener.ExecConfig) (havener.ExecResult, error) {
+	return havener.ExitCodeResult(h.exec(ExecTarget{Node: &node}, execConfig))
+}
+
+// Executions returns the list of targets for which commands were executed
+func (h *Havener) Executions() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var result = make([]string, len(h.executions))
+	for i, target := range h.executions {
+		result[i] = target.String()
+	}
+
+	return result
+}
+
+func (h *Havener) exec(target ExecTarget, execConfig havener.ExecConfig) (havener.ExecResult, error) {
+	h.mu.Lock()
+	h.executions = append(h.executions, target)
+	h.mu.Unlock()
+
+	if h.Exec == nil {
+		return havener.ExecResult{}, nil
+	}
+
+	// The helper pod of a node execution is created by havener with a random
+	// name, so only the container name is known in advance
+	var namespace, pod, container = "", "", "node-exec-container"
+	if target.Pod != nil {
+		namespace, pod, container = target.Pod.Namespace, target.Pod.Name, target.Container
+	}
+
+	result, err := havener.RunExec(namespace, pod, container, execConfig, func(stdout io.Writer, stderr io.Writer) error {
+		execConfig.Stdout, execConfig.Stderr = stdout, stderr
+		return h.Exec(target, execConfig)
+	})
+
+	var execErr *havener.ExecError
+	if target.Node != nil && errors.As(err, &execErr) {
+		execErr.Node = target.Node.Name
+	}
+
+	return result, err
+}
@@ -0,0 +1,96 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package havenertest_test
+
+import (
+	"bytes"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/homeport/havener/pkg/havener/havenertest"
+
+	"github.com/homeport/havener/pkg/havener"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/exec"
+)
+
+var _ = Describe("Fake Havener", func() {
+	var (
+		hvnr *Havener
+		pod  *corev1.Pod
+	)
+
+	BeforeEach(func() {
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api-0"}}
+		hvnr = NewHavener(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			pod,
+		)
+	})
+
+	It("should serve the objects it was set up with", func() {
+		Expect(hvnr.ClusterName()).To(Equal(DefaultClusterName))
+
+		pods, err := hvnr.ListPods()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pods).To(HaveLen(1))
+		Expect(pods[0].Name).To(Equal("api-0"))
+	})
+
+	It("should use the exec stub to return scripted output and exit codes", func() {
+		hvnr.Exec = Reply("out", "err", 42)
+
+		var stdout, stderr bytes.Buffer
+		err := hvnr.PodExec(pod, "api", havener.ExecConfig{Command: []string{"false"}, Stdout: &stdout, Stderr: &stderr})
+		Expect(err).To(HaveOccurred())
+
+		var exitErr exec.CodeExitError
+		Expect(errors.As(err, &exitErr)).To(BeTrue())
+		Expect(exitErr.Code).To(Equal(42))
+
+		var execErr *havener.ExecError
+		Expect(errors.As(err, &execErr)).To(BeTrue())
+		Expect(execErr.Pod).To(Equal("api-0"))
+		Expect(execErr.Container).To(Equal("api"))
+		Expect(execErr.ExitCode).To(Equal(42))
+		Expect(execErr.Stderr).To(Equal("err"))
+		Expect(stdout.String()).To(Equal("out"))
+		Expect(stderr.String()).To(Equal("err"))
+
+		result, err := hvnr.PodExecWithResult(pod, "api", havener.ExecConfig{Command: []string{"false"}, Stdout: &stdout, Stderr: &stderr})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.ExitCode).To(Equal(42))
+		Expect(result.StdoutBytes).To(BeEquivalentTo(3))
+		Expect(result.StderrBytes).To(BeEquivalentTo(3))
+
+		err = hvnr.NodeExec(corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}, havener.NodeExecHelperPodConfig{}, havener.ExecConfig{})
+		Expect(errors.As(err, &execErr)).To(BeTrue())
+		Expect(execErr.Namespace).To(BeEmpty())
+		Expect(execErr.Pod).To(BeEmpty())
+		Expect(execErr.Node).To(Equal("node-1"))
+		Expect(execErr.ExitCode).To(Equal(42))
+		Expect(hvnr.Executions()).To(Equal([]string{"default/api-0/api", "default/api-0/api", "node-1"}))
+	})
+})
@@ -21,6 +21,8 @@
 package havener
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -32,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
 	"k8s.io/utils/ptr"
 
 	"github.com/gonvenience/text"
@@ -50,17 +53,136 @@ type NodeExecHelperPodConfig struct {
 	WaitTimeout    time.Duration
 }
 
+// stderrTailLimit is the number of bytes of the standard error output that
+// are kept for the error details of a failed command execution
+const stderrTailLimit = 4 * 1024
+
 type ExecConfig struct {
 	Command []string
 	Stdin   io.Reader
 	Stdout  io.Writer
 	Stderr  io.Writer
 	TTY     bool
+
+	// Env contains additional environment variables in KEY=VALUE format
+	Env []string
+
+	// WorkDir is the directory in which the command is executed
+	WorkDir string
+
+	// Timeout is the maximum duration of the command execution, zero means
+	// there is no time limit
+	Timeout time.Duration
+
+	// RunAsUser is the name or ID of the user that executes the command
+	RunAsUser string
+
+	// Context can be used to cancel the command execution in addition to the
+	// context of the Havener
+	Context context.Context
+}
+
+// wrappedCommand returns the command of the exec config. In case environment
+// variables, a working directory, or a user are configured, the command is
+// wrapped into a shell script that applies these settings, which requires
+// `/bin/sh` (and `su` for a user) to be available.
+func wrappedCommand(execConfig ExecConfig) ([]string, error) {
+	if len(execConfig.Env) == 0 && execConfig.WorkDir == "" && execConfig.RunAsUser == "" {
+		return execConfig.Command, nil
+	}
+
+	var script []string
+	if execConfig.WorkDir != "" {
+		script = append(script, "cd", shellQuote(execConfig.WorkDir), "&&")
+	}
+
+	script = append(script, "exec")
+	if len(execConfig.Env) > 0 {
+		script = append(script, "env")
+		for _, env := range execConfig.Env {
+			if name, _, ok := strings.Cut(env, "="); !ok || name == "" {
+				return nil, fmt.Errorf("invalid environment variable %q, expected KEY=VALUE format", env)
+			}
+
+			script = append(script, shellQuote(env))
+		}
+	}
+
+	for _, arg := range execConfig.Command {
+		script = append(script, shellQuote(arg))
+	}
+
+	if execConfig.RunAsUser != "" {
+		return []string{"su", "-s", "/bin/sh", execConfig.RunAsUser, "-c", strings.Join(script, " ")}, nil
+	}
+
+	return []string{"/bin/sh", "-c", strings.Join(script, " ")}, nil
+}
+
+// shellQuote quotes the given string so that a POSIX shell reads it as one word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+// ExecResult contains the details of a command execution
+type ExecResult struct {
+	// ExitCode is the exit code of the command
+	ExitCode int
+
+	// Duration is the time the command execution took
+	Duration time.Duration
+
+	// StdoutBytes is the number of bytes the command wrote to standard output
+	StdoutBytes int64
+
+	// StderrBytes is the number of bytes the command wrote to standard error
+	StderrBytes int64
+}
+
+// countingWriter is a writer that counts the bytes written to it
+type countingWriter struct {
+	io.Writer
+	count int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.count += int64(n)
+	return n, err
+}
+
+// ExitCodeResult turns the error of a command that terminated with a non-zero
+// exit code into a result, only errors other than the exit code remain
+func ExitCodeResult(result ExecResult, err error) (ExecResult, error) {
+	var execErr *ExecError
+	if errors.As(err, &execErr) && execErr.ExitCode >= 0 {
+		return result, nil
+	}
+
+	return result, err
 }
 
 // PodExec executes the provided command in the referenced pod's container.
 func (h *Hvnr) PodExec(pod *corev1.Pod, container string, execConfig ExecConfig) error {
-	logf(Verbose, "Executing command on pod: `%v`", strings.Join(execConfig.Command, " "))
+	_, err := h.podExec(pod, container, execConfig)
+	return err
+}
+
+// PodExecWithResult executes the provided command in the referenced pod's
+// container and returns the result of the execution. In contrast to PodExec,
+// a non-zero exit code of the command is not an error, but part of the result.
+func (h *Hvnr) PodExecWithResult(pod *corev1.Pod, container string, execConfig ExecConfig) (ExecResult, error) {
+	return ExitCodeResult(h.podExec(pod, container, execConfig))
+}
+
+func (h *Hvnr) podExec(pod *corev1.Pod, container string, execConfig ExecConfig) (ExecResult, error) {
+	logger := h.logger.With("namespace", pod.Namespace, "pod", pod.Name, "container", container)
+	logger.Info("Executing command on pod", "command", strings.Join(execConfig.Command, " "))
+
+	command, err := wrappedCommand(execConfig)
+	if err != nil {
+		return ExecResult{ExitCode: -1}, err
+	}
 
 	req := h.client.CoreV1().RESTClient().Post().
 		Resource("pods").
@@ -69,7 +191,7 @@ func (h *Hvnr) PodExec(pod *corev1.Pod, container string, execConfig ExecConfig)
 		SubResource("exec").
 		VersionedParams(&corev1.PodExecOptions{
 			Container: container,
-			Command:   execConfig.Command,
+			Command:   command,
 			Stdin:     execConfig.Stdin != nil,
 			Stdout:    execConfig.Stdout != nil,
 			Stderr:    execConfig.Stderr != nil,
@@ -78,7 +200,7 @@ func (h *Hvnr) PodExec(pod *corev1.Pod, container string, execConfig ExecConfig)
 
 	executor, err := remotecommand.NewSPDYExecutor(h.restconfig, "POST", req.URL())
 	if err != nil {
-		return fmt.Errorf("failed to initialize remote executor: %w", err)
+		return ExecResult{ExitCode: -1}, fmt.Errorf("failed to initialize remote executor: %w", err)
 	}
 
 	var tsq *terminalSizeQueue
@@ -93,7 +215,7 @@ func (h *Hvnr) PodExec(pod *corev1.Pod, container string, execConfig ExecConfig)
 	if execConfig.TTY {
 		oldState, err := term.MakeRaw(0)
 		if err != nil {
-			return fmt.Errorf("failed to use raw terminal: %w", err)
+			return ExecResult{ExitCode: -1}, fmt.Errorf("failed to use raw terminal: %w", err)
 		}
 		defer func() { _ = term.Restore(0, oldState) }()
 	}
@@ -106,16 +228,117 @@ func (h *Hvnr) PodExec(pod *corev1.Pod, container string, execConfig ExecConfig)
 		TerminalSizeQueue: tsq,
 	}
 
-	if err = executor.StreamWithContext(h.ctx, streamOption); err != nil {
-		return fmt.Errorf("failed to execute command on pod %s, container %s: %w", pod.Name, container, err)
+	var ctx = h.ctx
+	if execConfig.Context != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+
+		stop := context.AfterFunc(execConfig.Context, cancel)
+		defer stop()
+	}
+
+	if execConfig.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, execConfig.Timeout)
+		defer cancel()
 	}
 
-	logf(Verbose, "Successfully executed command.")
-	return nil
+	result, err := RunExec(pod.Namespace, pod.Name, container, execConfig, func(stdout io.Writer, stderr io.Writer) error {
+		streamOption.Stdout, streamOption.Stderr = stdout, stderr
+		err := executor.StreamWithContext(ctx, streamOption)
+
+		// Make the deadline the reason, the stream error is only a consequence
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("command did not finish within %v: %w", execConfig.Timeout, context.DeadlineExceeded)
+		}
+
+		return err
+	})
+
+	if err != nil {
+		logger.Info("Command execution failed", "exitCode", result.ExitCode, "duration", result.Duration)
+		return result, err
+	}
+
+	logger.Info("Successfully executed command", "duration", result.Duration)
+	return result, nil
+}
+
+// RunExec runs a command execution using the provided function and reports
+// the result and error the same way the command executions of havener do.
+// The function is called with the output streams of the exec config, which
+// are wrapped to count the bytes written. Other implementations of the
+// Havener interface, for example fakes in tests, can use it to behave the
+// same way.
+func RunExec(namespace string, pod string, container string, execConfig ExecConfig, run func(stdout io.Writer, stderr io.Writer) error) (ExecResult, error) {
+	var stdout, stderr io.Writer
+	var stdoutCounter, stderrCounter *countingWriter
+	if execConfig.Stdout != nil {
+		stdoutCounter = &countingWriter{Writer: execConfig.Stdout}
+		stdout = stdoutCounter
+	}
+
+	// Keep the end of the standard error output for the error details
+	var stderrTail = &tailBuffer{limit: stderrTailLimit}
+	if execConfig.Stderr != nil {
+		stderrCounter = &countingWriter{Writer: io.MultiWriter(execConfig.Stderr, stderrTail)}
+		stderr = stderrCounter
+	}
+
+	var start = time.Now()
+	err := run(stdout, stderr)
+
+	var result = ExecResult{Duration: time.Since(start)}
+	if stdoutCounter != nil {
+		result.StdoutBytes = stdoutCounter.count
+	}
+
+	if stderrCounter != nil {
+		result.StderrBytes = stderrCounter.count
+	}
+
+	if err == nil {
+		return result, nil
+	}
+
+	result.ExitCode = -1
+	var exitErr exec.ExitError
+	if errors.As(err, &exitErr) {
+		result.ExitCode = exitErr.ExitStatus()
+	}
+
+	return result, &ExecError{
+		Namespace: namespace,
+		Pod:       pod,
+		Container: container,
+		ExitCode:  result.ExitCode,
+		Stderr:    stderrTail.String(),
+		Err:       err,
+	}
 }
 
 // NodeExec executes the provided command on the given node.
 func (h *Hvnr) NodeExec(node corev1.Node, hlpPodConfig NodeExecHelperPodConfig, execConfig ExecConfig) error {
+	_, err := h.nodeExec(node, hlpPodConfig, execConfig)
+	return err
+}
+
+// NodeExecWithResult executes the provided command on the given node and
+// returns the result of the execution. In contrast to NodeExec, a non-zero
+// exit code of the command is not an error, but part of the result.
+func (h *Hvnr) NodeExecWithResult(node corev1.Node, hlpPodConfig NodeExecHelperPodConfig, execConfig ExecConfig) (ExecResult, error) {
+	return ExitCodeResult(h.nodeExec(node, hlpPodConfig, execConfig))
+}
+
+func (h *Hvnr) nodeExec(node corev1.Node, hlpPodConfig NodeExecHelperPodConfig, execConfig ExecConfig) (ExecResult, error) {
+	// Wrap the command before entering the host namespaces, so that the
+	// settings apply to the host and not to the helper pod container
+	command, err := wrappedCommand(execConfig)
+	if err != nil {
+		return ExecResult{ExitCode: -1}, err
+	}
+
 	hlpPodConfig.podName = text.RandomStringWithPrefix("node-exec-", 15) // unique pod name
 	hlpPodConfig.namespace = "kube-system"
 
@@ -126,7 +349,7 @@ func (h *Hvnr) NodeExec(node corev1.Node, hlpPodConfig NodeExecHelperPodConfig,
 
 	pod, err := h.preparePodOnNode(node, hlpPodConfig)
 	if err != nil {
-		return err
+		return ExecResult{ExitCode: -1}, err
 	}
 
 	// Unset the stderr in case TTY is set
@@ -136,18 +359,28 @@ func (h *Hvnr) NodeExec(node corev1.Node, hlpPodConfig NodeExecHelperPodConfig,
 	}
 
 	// Execute command on pod and redirect output to users provided stdout and stderr
-	logf(Verbose, "Executing command on node: `%v`", strings.Join(execConfig.Command, " "))
+	h.logger.Info("Executing command on node", "node", node.Name, "command", strings.Join(execConfig.Command, " "))
 
-	return h.PodExec(
+	result, err := h.podExec(
 		pod, "node-exec-container",
 		ExecConfig{
-			Command: append([]string{"nsenter", "--target", "1", "--mount", "--uts", "--ipc", "--net", "--pid", "--"}, execConfig.Command...),
+			Command: append([]string{"nsenter", "--target", "1", "--mount", "--uts", "--ipc", "--net", "--pid", "--"}, command...),
 			Stdin:   execConfig.Stdin,
 			Stdout:  execConfig.Stdout,
 			Stderr:  execConfig.Stderr,
 			TTY:     execConfig.TTY,
+			Timeout: execConfig.Timeout,
+			Context: execConfig.Context,
 		},
 	)
+
+	// Name the node in the error, the helper pod is only an implementation detail
+	var execErr *ExecError
+	if errors.As(err, &execErr) {
+		execErr.Node = node.Name
+	}
+
+	return result, err
 }
 
 func (h *Hvnr) preparePodOnNode(node corev1.Node, hlpPodConfig NodeExecHelperPodConfig) (*corev1.Pod, error) {
@@ -198,13 +431,14 @@ func (h *Hvnr) preparePodOnNode(node corev1.Node, hlpPodConfig NodeExecHelperPod
 	}
 
 	// Create pod in given namespace based on configuration
-	logf(Verbose, "Creating temporary pod _%s_/*%s*", hlpPodConfig.namespace, hlpPodConfig.podName)
+	logger := h.logger.With("namespace", hlpPodConfig.namespace, "pod", hlpPodConfig.podName, "node", node.Name)
+	logger.Info("Creating temporary pod")
 	pod, err := h.client.CoreV1().Pods(hlpPodConfig.namespace).Create(h.ctx, pod, metav1.CreateOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	logf(Verbose, "Waiting for temporary pod to be started...")
+	logger.Info("Waiting for temporary pod to be started")
 	if err := h.waitForPodReadiness(pod, hlpPodConfig.WaitTimeout); err != nil {
 		return nil, err
 	}
@@ -250,12 +484,12 @@ func (h *Hvnr) waitForPodReadiness(pod *corev1.Pod, waitTimeout time.Duration) e
 				description = "Unable to provide further details regarding the state of the pod."
 			}
 
-			return fmt.Errorf("Giving up waiting for pod %s in namespace %s to become ready within %v: %w",
-				pod.Name,
-				pod.Namespace,
-				waitTimeout,
-				fmt.Errorf("status of pod at the moment of the timeout:\n\n%s", description),
-			)
+			return &HelperPodTimeoutError{
+				Namespace:   pod.Namespace,
+				Pod:         pod.Name,
+				Timeout:     waitTimeout,
+				Description: description,
+			}
 		}
 	}
 }
@@ -0,0 +1,70 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package havener_test
+
+import (
+	"os/exec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/homeport/havener/pkg/havener"
+)
+
+var _ = Describe("Command execution", func() {
+	Context("wrapping the command", func() {
+		It("should leave the command untouched without any settings", func() {
+			Expect(WrappedCommand(ExecConfig{Command: []string{"cat", "/etc/hosts"}})).
+				To(Equal([]string{"cat", "/etc/hosts"}))
+		})
+
+		It("should apply working directory and environment variables through a shell", func() {
+			Expect(WrappedCommand(ExecConfig{
+				Command: []string{"printenv", "GREETING"},
+				Env:     []string{"GREETING=hello world"},
+				WorkDir: "/var/vcap",
+			})).To(Equal([]string{"/bin/sh", "-c", `cd '/var/vcap' && exec env 'GREETING=hello world' 'printenv' 'GREETING'`}))
+		})
+
+		It("should run the command as the configured user", func() {
+			Expect(WrappedCommand(ExecConfig{Command: []string{"id", "-u"}, RunAsUser: "vcap"})).
+				To(Equal([]string{"su", "-s", "/bin/sh", "vcap", "-c", `exec 'id' '-u'`}))
+		})
+
+		It("should fail for environment variables without a name", func() {
+			_, err := WrappedCommand(ExecConfig{Command: []string{"env"}, Env: []string{"=foobar"}})
+			Expect(err).To(MatchError(ContainSubstring("expected KEY=VALUE format")))
+		})
+
+		It("should keep arguments with quotes intact when run by a shell", func() {
+			command, err := WrappedCommand(ExecConfig{
+				Command: []string{"/bin/sh", "-c", `printf '%s:%s' "$FOO" "$PWD"`},
+				Env:     []string{"FOO=it's $HOME"},
+				WorkDir: "/",
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			output, err := exec.Command(command[0], command[1:]...).Output()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(output)).To(Equal("it's $HOME:/"))
+		})
+	})
+})
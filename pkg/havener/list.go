@@ -22,22 +22,134 @@ package havener
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 	"sync"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
-
-	"github.com/gonvenience/text"
+	toolscache "k8s.io/client-go/tools/cache"
 )
 
+// listPageSize is the maximum number of items requested with one list call,
+// which bounds the size of the individual API server responses
+const listPageSize = 500
+
+// listInPages calls the provided list function with the page size limit set
+// as long as the list function returns a continue token for the next page
+func listInPages(opts metav1.ListOptions, list func(metav1.ListOptions) (string, error)) error {
+	opts.Limit = listPageSize
+	for {
+		next, err := list(opts)
+		if err != nil {
+			return err
+		}
+
+		if next == "" {
+			return nil
+		}
+
+		opts.Continue = next
+	}
+}
+
+// ListFilter defines which resources are to be listed
+type ListFilter struct {
+	// Namespaces to list the resources in, if empty, all namespaces are used
+	// (not used for cluster scoped resources like nodes)
+	Namespaces []string
+
+	// LabelSelector restricts the list to resources with matching labels,
+	// for example app=router
+	LabelSelector string
+
+	// FieldSelector restricts the list to resources with matching fields,
+	// for example status.phase=Running
+	FieldSelector string
+}
+
+func (f ListFilter) String() string {
+	var result = "all namespaces"
+	if len(f.Namespaces) > 0 {
+		result = strings.Join(f.Namespaces, ", ")
+	}
+
+	var selectors []string
+	for _, selector := range []string{f.LabelSelector, f.FieldSelector} {
+		if selector != "" {
+			selectors = append(selectors, selector)
+		}
+	}
+
+	if len(selectors) > 0 {
+		result += fmt.Sprintf(" matching %s", strings.Join(selectors, ", "))
+	}
+
+	return result
+}
+
+// LogValue returns the filter as a group of the set fields for structured logs
+func (f ListFilter) LogValue() slog.Value {
+	var attrs []slog.Attr
+	if len(f.Namespaces) > 0 {
+		attrs = append(attrs, slog.String("namespaces", strings.Join(f.Namespaces, ",")))
+	}
+
+	if f.LabelSelector != "" {
+		attrs = append(attrs, slog.String("labelSelector", f.LabelSelector))
+	}
+
+	if f.FieldSelector != "" {
+		attrs = append(attrs, slog.String("fieldSelector", f.FieldSelector))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+func (f ListFilter) listOptions() metav1.ListOptions {
+	return metav1.ListOptions{
+		LabelSelector: f.LabelSelector,
+		FieldSelector: f.FieldSelector,
+	}
+}
+
+// cachedList returns the label selector to be used with the cache listers, in
+// case the list can be served from the cache, which is not possible for field
+// selectors, since the listers only support label selectors
+func (h *Hvnr) cachedList(filter ListFilter) (labels.Selector, bool, error) {
+	if h.cache == nil || filter.FieldSelector != "" {
+		return nil, false, nil
+	}
+
+	selector, err := labels.Parse(filter.LabelSelector)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid label selector %q: %w", filter.LabelSelector, err)
+	}
+
+	return selector, true, nil
+}
+
+// fromCache starts the informer of the given resource type and reports whether
+// the list can be served from the cache. In case the informer cannot populate
+// the cache, for example because the user is not allowed to list the resources
+// in all namespaces, the list call is used instead.
+func (h *Hvnr) fromCache(resource string, informer toolscache.SharedIndexInformer) bool {
+	if err := h.cache.start(resource, informer); err != nil {
+		h.logger.Info("Listing without cache", "resource", resource, "error", err)
+		return false
+	}
+
+	return true
+}
+
 // ListNamespaces lists all namespaces
 func (h *Hvnr) ListNamespaces() ([]string, error) {
-	logf(Verbose, "Listing all namespaces")
+	h.logger.Info("Listing all namespaces")
 
 	namespaceList, err := h.client.CoreV1().Namespaces().List(h.ctx, metav1.ListOptions{})
 	if err != nil {
@@ -49,27 +161,68 @@ func (h *Hvnr) ListNamespaces() ([]string, error) {
 		result[i] = namespace.Name
 	}
 
-	logf(Verbose, "Found %s", text.Plural(len(result), "namespace"))
+	h.logger.Info("Found namespaces", "count", len(result))
 	return result, nil
 }
 
 // ListPods lists all pods in the given namespaces, if no namespace is given,
-// then all namespaces currently available in the cluster will be used
+// then the pods of all namespaces are listed using paginated list calls
 func (h *Hvnr) ListPods(namespaces ...string) ([]*corev1.Pod, error) {
-	logf(Verbose, "Listing all pods in %s", func() string {
-		if len(namespaces) == 0 {
-			return "all namespaces"
+	return h.ListPodsWithOptions(ListFilter{Namespaces: namespaces})
+}
+
+// ListPodsWithOptions lists all pods that match the provided filter
+func (h *Hvnr) ListPodsWithOptions(filter ListFilter) ([]*corev1.Pod, error) {
+	h.logger.Info("Listing pods", "filter", filter)
+
+	selector, cached, err := h.cachedList(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached && h.fromCache("pods", h.cache.factory.Core().V1().Pods().Informer()) {
+		lister := h.cache.factory.Core().V1().Pods().Lister()
+
+		if len(filter.Namespaces) == 0 {
+			return lister.List(selector)
+		}
+
+		var result []*corev1.Pod
+		for _, namespace := range filter.Namespaces {
+			pods, err := lister.Pods(namespace).List(selector)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, pods...)
 		}
 
-		return strings.Join(namespaces, ", ")
-	}())
+		return result, nil
+	}
+
+	// Without a namespace filter, use one (paginated) list call for all pods
+	// in all namespaces instead of one list call per namespace
+	if len(filter.Namespaces) == 0 {
+		var result []*corev1.Pod
+		err := listInPages(filter.listOptions(), func(opts metav1.ListOptions) (string, error) {
+			listResp, err := h.client.CoreV1().Pods(metav1.NamespaceAll).List(h.ctx, opts)
+			if err != nil {
+				return "", err
+			}
+
+			for i := range listResp.Items {
+				result = append(result, &listResp.Items[i])
+			}
+
+			return listResp.Continue, nil
+		})
 
-	if len(namespaces) == 0 {
-		var err error
-		namespaces, err = h.ListNamespaces()
 		if err != nil {
 			return nil, err
 		}
+
+		h.logger.Info("Found pods", "count", len(result))
+		return result, nil
 	}
 
 	type list struct {
@@ -86,21 +239,21 @@ func (h *Hvnr) ListPods(namespaces ...string) ([]*corev1.Pod, error) {
 
 	var result list
 
-	var work = make(chan string, len(namespaces))
-	var errs = make(chan error)
+	var work = make(chan string, len(filter.Namespaces))
+	var errs = make(chan error, len(filter.Namespaces))
 
-	for _, namespace := range namespaces {
+	for _, namespace := range filter.Namespaces {
 		work <- namespace
 	}
 	close(work)
 
 	var wg sync.WaitGroup
-	for i := 0; i < min(concurrency, len(namespaces)); i++ {
+	for i := 0; i < min(concurrency, len(filter.Namespaces)); i++ {
 		wg.Add(1)
 		go func(n int) {
 			defer wg.Done()
 			for namespace := range work {
-				listResp, err := h.client.CoreV1().Pods(namespace).List(h.ctx, metav1.ListOptions{})
+				listResp, err := h.client.CoreV1().Pods(namespace).List(h.ctx, filter.listOptions())
 				if err != nil {
 					errs <- err
 					continue
@@ -126,17 +279,62 @@ func (h *Hvnr) ListPods(namespaces ...string) ([]*corev1.Pod, error) {
 }
 
 // ListSecrets lists all secrets in the given namespaces, if no namespace is given,
-// then all namespaces currently available in the cluster will be used
-func (h *Hvnr) ListSecrets(namespaces ...string) (result []*corev1.Secret, err error) {
-	if len(namespaces) == 0 {
-		namespaces, err = h.ListNamespaces()
+// then the secrets of all namespaces are listed using paginated list calls
+func (h *Hvnr) ListSecrets(namespaces ...string) ([]*corev1.Secret, error) {
+	return h.ListSecretsWithOptions(ListFilter{Namespaces: namespaces})
+}
+
+// ListSecretsWithOptions lists all secrets that match the provided filter
+func (h *Hvnr) ListSecretsWithOptions(filter ListFilter) (result []*corev1.Secret, err error) {
+	h.logger.Info("Listing secrets", "filter", filter)
+
+	selector, cached, err := h.cachedList(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached && h.fromCache("secrets", h.cache.factory.Core().V1().Secrets().Informer()) {
+		lister := h.cache.factory.Core().V1().Secrets().Lister()
+
+		if len(filter.Namespaces) == 0 {
+			return lister.List(selector)
+		}
+
+		for _, namespace := range filter.Namespaces {
+			list, err := lister.Secrets(namespace).List(selector)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, list...)
+		}
+
+		return result, nil
+	}
+
+	if len(filter.Namespaces) == 0 {
+		err = listInPages(filter.listOptions(), func(opts metav1.ListOptions) (string, error) {
+			listResp, err := h.client.CoreV1().Secrets(metav1.NamespaceAll).List(h.ctx, opts)
+			if err != nil {
+				return "", err
+			}
+
+			for i := range listResp.Items {
+				result = append(result, &listResp.Items[i])
+			}
+
+			return listResp.Continue, nil
+		})
+
 		if err != nil {
 			return nil, err
 		}
+
+		return result, nil
 	}
 
-	for _, namespace := range namespaces {
-		listResp, err := h.client.CoreV1().Secrets(namespace).List(h.ctx, metav1.ListOptions{})
+	for _, namespace := range filter.Namespaces {
+		listResp, err := h.client.CoreV1().Secrets(namespace).List(h.ctx, filter.listOptions())
 		if err != nil {
 			return nil, err
 		}
@@ -150,17 +348,62 @@ func (h *Hvnr) ListSecrets(namespaces ...string) (result []*corev1.Secret, err e
 }
 
 // ListConfigMaps lists all confimaps in the given namespaces, if no namespace is given,
-// then all namespaces currently available in the cluster will be used
-func (h *Hvnr) ListConfigMaps(namespaces ...string) (result []*corev1.ConfigMap, err error) {
-	if len(namespaces) == 0 {
-		namespaces, err = h.ListNamespaces()
+// then the configmaps of all namespaces are listed using paginated list calls
+func (h *Hvnr) ListConfigMaps(namespaces ...string) ([]*corev1.ConfigMap, error) {
+	return h.ListConfigMapsWithOptions(ListFilter{Namespaces: namespaces})
+}
+
+// ListConfigMapsWithOptions lists all configmaps that match the provided filter
+func (h *Hvnr) ListConfigMapsWithOptions(filter ListFilter) (result []*corev1.ConfigMap, err error) {
+	h.logger.Info("Listing configmaps", "filter", filter)
+
+	selector, cached, err := h.cachedList(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached && h.fromCache("configmaps", h.cache.factory.Core().V1().ConfigMaps().Informer()) {
+		lister := h.cache.factory.Core().V1().ConfigMaps().Lister()
+
+		if len(filter.Namespaces) == 0 {
+			return lister.List(selector)
+		}
+
+		for _, namespace := range filter.Namespaces {
+			list, err := lister.ConfigMaps(namespace).List(selector)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, list...)
+		}
+
+		return result, nil
+	}
+
+	if len(filter.Namespaces) == 0 {
+		err = listInPages(filter.listOptions(), func(opts metav1.ListOptions) (string, error) {
+			listResp, err := h.client.CoreV1().ConfigMaps(metav1.NamespaceAll).List(h.ctx, opts)
+			if err != nil {
+				return "", err
+			}
+
+			for i := range listResp.Items {
+				result = append(result, &listResp.Items[i])
+			}
+
+			return listResp.Continue, nil
+		})
+
 		if err != nil {
 			return nil, err
 		}
+
+		return result, nil
 	}
 
-	for _, namespace := range namespaces {
-		listResp, err := h.client.CoreV1().ConfigMaps(namespace).List(h.ctx, metav1.ListOptions{})
+	for _, namespace := range filter.Namespaces {
+		listResp, err := h.client.CoreV1().ConfigMaps(namespace).List(h.ctx, filter.listOptions())
 		if err != nil {
 			return nil, err
 		}
@@ -173,6 +416,54 @@ func (h *Hvnr) ListConfigMaps(namespaces ...string) (result []*corev1.ConfigMap,
 	return result, nil
 }
 
+// ListEvents lists all events in the given namespaces, if no namespace is given,
+// then the events of all namespaces are listed using paginated list calls
+func (h *Hvnr) ListEvents(namespaces ...string) (result []*corev1.Event, err error) {
+	if h.cache != nil && h.fromCache("events", h.cache.factory.Core().V1().Events().Informer()) {
+		lister := h.cache.factory.Core().V1().Events().Lister()
+
+		if len(namespaces) == 0 {
+			return lister.List(labels.Everything())
+		}
+
+		for _, namespace := range namespaces {
+			list, err := lister.Events(namespace).List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, list...)
+		}
+
+		return result, nil
+	}
+
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	for _, namespace := range namespaces {
+		err = listInPages(metav1.ListOptions{}, func(opts metav1.ListOptions) (string, error) {
+			listResp, err := h.client.CoreV1().Events(namespace).List(h.ctx, opts)
+			if err != nil {
+				return "", err
+			}
+
+			for i := range listResp.Items {
+				result = append(result, &listResp.Items[i])
+			}
+
+			return listResp.Continue, nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
 // ListCustomResourceDefinition lists all instances of an specific CRD
 func (h *Hvnr) ListCustomResourceDefinition(crdName string) (result []unstructured.Unstructured, err error) {
 	var runtimeClassGVR schema.GroupVersionResource
@@ -190,12 +481,39 @@ func (h *Hvnr) ListCustomResourceDefinition(crdName string) (result []unstructur
 		return list.Items, nil
 	}
 
-	return nil, fmt.Errorf("desired resource %s, was not found", crdName)
+	return nil, &ResourceNotFoundError{Kind: KindCustomResource, Name: crdName}
 }
 
 // ListNodes returns a list of the nodes in the cluster
 func (h *Hvnr) ListNodes() ([]corev1.Node, error) {
-	nodeList, err := h.client.CoreV1().Nodes().List(h.ctx, metav1.ListOptions{})
+	return h.ListNodesWithOptions(ListFilter{})
+}
+
+// ListNodesWithOptions returns a list of the nodes in the cluster that match
+// the provided filter, the namespaces of the filter are not used
+func (h *Hvnr) ListNodesWithOptions(filter ListFilter) ([]corev1.Node, error) {
+	selector, cached, err := h.cachedList(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached && h.fromCache("nodes", h.cache.factory.Core().V1().Nodes().Informer()) {
+		lister := h.cache.factory.Core().V1().Nodes().Lister()
+
+		nodes, err := lister.List(selector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get list of nodes: %w", err)
+		}
+
+		result := make([]corev1.Node, len(nodes))
+		for i, node := range nodes {
+			result[i] = *node
+		}
+
+		return result, nil
+	}
+
+	nodeList, err := h.client.CoreV1().Nodes().List(h.ctx, filter.listOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed to get list of nodes: %w", err)
 	}
@@ -205,13 +523,13 @@ func (h *Hvnr) ListNodes() ([]corev1.Node, error) {
 
 // ListNodeNames returns a list of the names of the nodes in the cluster
 func (h *Hvnr) ListNodeNames() ([]string, error) {
-	nodeList, err := h.client.CoreV1().Nodes().List(h.ctx, metav1.ListOptions{})
+	nodes, err := h.ListNodes()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get list of nodes: %w", err)
+		return nil, err
 	}
 
-	result := make([]string, len(nodeList.Items))
-	for i, node := range nodeList.Items {
+	result := make([]string, len(nodes))
+	for i, node := range nodes {
 		result[i] = node.Name
 	}
 
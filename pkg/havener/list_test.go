@@ -0,0 +1,297 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package havener_test
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/homeport/havener/pkg/havener"
+	"github.com/homeport/havener/pkg/havener/havenertest"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("Listing resources", func() {
+	Context("listing pods of all namespaces", func() {
+		var (
+			hvnr  *havenertest.Havener
+			calls int
+		)
+
+		BeforeEach(func() {
+			hvnr = havenertest.NewHavener()
+			calls = 0
+
+			// Simulate an API server that returns five pods in pages of at most
+			// two pods, the continue token is only handed out until the last page
+			hvnr.Clientset.PrependReactor("list", "pods", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+				offset := calls * 2
+				calls++
+
+				result := &corev1.PodList{}
+				for i := offset; i < offset+2 && i < 5; i++ {
+					result.Items = append(result.Items, corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{Namespace: fmt.Sprintf("ns-%d", i), Name: fmt.Sprintf("pod-%d", i)},
+					})
+				}
+
+				if offset+2 < 5 {
+					result.Continue = strconv.Itoa(offset + 2)
+				}
+
+				return true, result, nil
+			})
+		})
+
+		It("should use paginated list calls instead of one call per namespace", func() {
+			pods, err := hvnr.ListPods()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pods).To(HaveLen(5))
+			Expect(calls).To(Equal(3))
+
+			for _, action := range hvnr.Clientset.Actions() {
+				Expect(action.GetResource().Resource).To(Equal("pods"))
+				Expect(action.GetNamespace()).To(BeEmpty())
+			}
+		})
+	})
+
+	Context("listing secrets and configmaps of all namespaces", func() {
+		It("should return the resources of all namespaces", func() {
+			hvnr := havenertest.NewHavener(
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "one", Name: "secret"}},
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "two", Name: "secret"}},
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "one", Name: "config"}},
+			)
+
+			secrets, err := hvnr.ListSecrets()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secrets).To(HaveLen(2))
+
+			configMaps, err := hvnr.ListConfigMaps()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(configMaps).To(HaveLen(1))
+		})
+	})
+
+	Context("listing resources using a filter", func() {
+		var objects = []runtime.Object{
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "one", Name: "router-0", Labels: map[string]string{"app": "router"}}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "two", Name: "router-1", Labels: map[string]string{"app": "router"}}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "one", Name: "api-0", Labels: map[string]string{"app": "api"}}},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-0", Labels: map[string]string{"kubernetes.io/arch": "arm64"}}},
+			&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"kubernetes.io/arch": "amd64"}}},
+		}
+
+		var verify = func(hvnr havener.Havener) {
+			pods, err := hvnr.ListPodsWithOptions(havener.ListFilter{LabelSelector: "app=router"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pods).To(HaveLen(2))
+
+			pods, err = hvnr.ListPodsWithOptions(havener.ListFilter{Namespaces: []string{"one"}, LabelSelector: "app=router"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pods).To(HaveLen(1))
+			Expect(pods[0].Name).To(Equal("router-0"))
+
+			nodes, err := hvnr.ListNodesWithOptions(havener.ListFilter{LabelSelector: "kubernetes.io/arch=arm64"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nodes).To(HaveLen(1))
+			Expect(nodes[0].Name).To(Equal("node-0"))
+		}
+
+		It("should list only the matching resources", func() {
+			verify(havenertest.NewHavener(objects...))
+		})
+
+		It("should list only the matching resources from the cache", func() {
+			hvnr, err := havener.NewHavener(
+				havener.WithClient(fake.NewSimpleClientset(objects...), nil),
+				havener.WithCache(),
+			)
+			Expect(err).ToNot(HaveOccurred())
+			defer func() { _ = hvnr.Close() }()
+
+			verify(hvnr)
+		})
+
+		It("should fail for an invalid label selector when using the cache", func() {
+			hvnr, err := havener.NewHavener(
+				havener.WithClient(fake.NewSimpleClientset(objects...), nil),
+				havener.WithCache(),
+			)
+			Expect(err).ToNot(HaveOccurred())
+			defer func() { _ = hvnr.Close() }()
+
+			_, err = hvnr.ListPodsWithOptions(havener.ListFilter{LabelSelector: "app in router"})
+			Expect(err).To(MatchError(ContainSubstring("invalid label selector")))
+		})
+	})
+
+	Context("using the cache", func() {
+		var (
+			clientset *fake.Clientset
+			hvnr      *havener.Hvnr
+		)
+
+		BeforeEach(func() {
+			var err error
+			clientset = fake.NewSimpleClientset(
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "one", Name: "foo"}},
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "two", Name: "bar"}},
+				&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}},
+				&corev1.Event{ObjectMeta: metav1.ObjectMeta{Namespace: "one", Name: "event"}},
+			)
+
+			hvnr, err = havener.NewHavener(
+				havener.WithClient(clientset, nil),
+				havener.WithCache(),
+			)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(hvnr.Close()).To(Succeed())
+		})
+
+		It("should serve lists from the cache", func() {
+			pods, err := hvnr.ListPods()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pods).To(HaveLen(2))
+
+			pods, err = hvnr.ListPods("one")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pods).To(HaveLen(1))
+			Expect(pods[0].Name).To(Equal("foo"))
+
+			nodes, err := hvnr.ListNodes()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nodes).To(HaveLen(1))
+
+			events, err := hvnr.ListEvents()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+
+			// Listing again must not result in additional list calls
+			var listCalls = func() (count int) {
+				for _, action := range clientset.Actions() {
+					if action.GetVerb() == "list" {
+						count++
+					}
+				}
+
+				return count
+			}
+
+			before := listCalls()
+			_, err = hvnr.ListPods()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(listCalls()).To(Equal(before))
+		})
+
+		It("should signal changes and keep the cache current", func() {
+			_, err := hvnr.ListPods()
+			Expect(err).ToNot(HaveOccurred())
+
+			// Drain the notifications of the initial population of the cache
+			select {
+			case <-hvnr.Changes():
+			default:
+			}
+
+			_, err = clientset.CoreV1().Pods("three").Create(hvnr.Context(), &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "three", Name: "new"}}, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Eventually(hvnr.Changes()).Should(Receive())
+			Eventually(func() ([]*corev1.Pod, error) { return hvnr.ListPods("three") }).Should(HaveLen(1))
+		})
+
+		It("should fail when the cache cannot be populated", func() {
+			clientset.PrependReactor("list", "secrets", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, fmt.Errorf("secrets are forbidden")
+			})
+
+			_, err := hvnr.ListSecrets()
+			Expect(err).To(MatchError(ContainSubstring("secrets are forbidden")))
+		})
+
+		It("should fall back to list calls when the cache cannot be populated", func() {
+			clientset.PrependReactor("list", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				if action.GetNamespace() == metav1.NamespaceAll {
+					return true, nil, fmt.Errorf("secrets are forbidden in all namespaces")
+				}
+
+				return false, nil, nil
+			})
+
+			_, err := hvnr.ListSecrets("one")
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = hvnr.ListSecrets("one")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should use the cache once it recovered from a temporary error", func() {
+			var failures atomic.Int32
+			clientset.PrependReactor("list", "secrets", func(_ k8stesting.Action) (bool, runtime.Object, error) {
+				if failures.Add(1) == 1 {
+					return true, nil, fmt.Errorf("connection refused")
+				}
+
+				return false, nil, nil
+			})
+
+			var listCalls = func() (count int) {
+				for _, action := range clientset.Actions() {
+					if action.GetVerb() == "list" && action.GetResource().Resource == "secrets" {
+						count++
+					}
+				}
+
+				return count
+			}
+
+			_, err := hvnr.ListSecrets()
+			Expect(err).ToNot(HaveOccurred())
+
+			Eventually(func() (int, error) {
+				before := listCalls()
+				_, err := hvnr.ListSecrets()
+				return listCalls() - before, err
+			}).WithTimeout(10 * time.Second).Should(BeZero())
+		})
+	})
+
+	It("should not signal changes without a cache", func() {
+		hvnr := havenertest.NewHavener()
+		Expect(hvnr.Changes()).To(BeNil())
+		Expect(hvnr.Close()).To(Succeed())
+	})
+})
@@ -21,17 +21,24 @@
 package havener
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
 	"time"
-
-	"github.com/gonvenience/bunt"
 )
 
 // LogLevel indicates the priority level of the log message
 // which can be used for filtering
+//
+// Deprecated: Use WithLogger to set a slog logger instead.
 type LogLevel int
 
 // LogMessage is a helper structure for transmitting log messages
 // to the logger
+//
+// Deprecated: Use WithLogger to set a slog logger instead.
 type LogMessage struct {
 	Message string
 	Level   LogLevel
@@ -46,6 +53,8 @@ type LogMessage struct {
 // - Verbose: print verbose/info logs and previous levels
 // - Debug: print debug logs and previous levels
 // - Trace: print all logs
+//
+// Deprecated: Use WithLogger to set a slog logger instead.
 const (
 	Off = LogLevel(iota)
 	Fatal
@@ -56,19 +65,99 @@ const (
 	Trace
 )
 
-var logChannel = make(chan LogMessage, 100)
+var (
+	logChannel     = make(chan LogMessage, 100)
+	logChannelUsed atomic.Bool
+)
 
 // GetLogChannel return the log channel to be used
-// within the internal package.
+// within the internal package. Once it was called, havener handles created
+// without a logger send their log messages to this channel. Messages are
+// dropped in case the channel is full.
+//
+// Deprecated: Use WithLogger to set a slog logger instead.
 func GetLogChannel() chan LogMessage {
+	logChannelUsed.Store(true)
 	return logChannel
 }
 
-// logf formats the message and sends it to the logger
-func logf(level LogLevel, message string, fArgs ...interface{}) {
-	logChannel <- LogMessage{
-		Message: bunt.Sprintf(message, fArgs...),
-		Level:   level,
-		Date:    time.Now(),
+// defaultLogger returns the logger for havener handles without a logger,
+// which discards all messages unless the log channel is used
+func defaultLogger() *slog.Logger {
+	if logChannelUsed.Load() {
+		return slog.New(&logChannelHandler{})
+	}
+
+	return slog.New(slog.DiscardHandler)
+}
+
+// logChannelHandler is a slog handler that sends the log records to the log
+// channel, with the attributes appended to the message
+type logChannelHandler struct {
+	attrs []slog.Attr
+	group string
+}
+
+// qualified returns the attribute with the group as a prefix of the key
+func (h *logChannelHandler) qualified(attr slog.Attr) slog.Attr {
+	if h.group != "" {
+		attr.Key = h.group + "." + attr.Key
 	}
+
+	return attr
+}
+
+func (h *logChannelHandler) Enabled(_ context.Context, _ slog.Level) bool {
+	return true
+}
+
+func (h *logChannelHandler) Handle(_ context.Context, record slog.Record) error {
+	var message strings.Builder
+	message.WriteString(record.Message)
+
+	for _, attr := range h.attrs {
+		fmt.Fprintf(&message, " %s=%v", attr.Key, attr.Value)
+	}
+
+	record.Attrs(func(attr slog.Attr) bool {
+		attr = h.qualified(attr)
+		fmt.Fprintf(&message, " %s=%v", attr.Key, attr.Value)
+		return true
+	})
+
+	var level = Verbose
+	switch {
+	case record.Level >= slog.LevelError:
+		level = Error
+
+	case record.Level >= slog.LevelWarn:
+		level = Warn
+
+	case record.Level < slog.LevelInfo:
+		level = Debug
+	}
+
+	select {
+	case logChannel <- LogMessage{Message: message.String(), Level: level, Date: record.Time}:
+	default:
+	}
+
+	return nil
+}
+
+func (h *logChannelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var result = &logChannelHandler{attrs: append([]slog.Attr{}, h.attrs...), group: h.group}
+	for _, attr := range attrs {
+		result.attrs = append(result.attrs, h.qualified(attr))
+	}
+
+	return result
+}
+
+func (h *logChannelHandler) WithGroup(name string) slog.Handler {
+	if h.group != "" {
+		name = h.group + "." + name
+	}
+
+	return &logChannelHandler{attrs: h.attrs, group: name}
 }
@@ -24,15 +24,17 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
-	"strings"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -43,17 +45,17 @@ import (
 // LogDirName is the subdirectory name where retrieved logs are stored
 const LogDirName = "retrieved-logs"
 
-var logFinds = []string{
-	"find var/vcap/sys -type f -name '*.log' -size +0c 2>/dev/null",
-	"find var/vcap/sys -type f -name '*.log.*' -size +0c 2>/dev/null",
-	"find var/vcap/monit -type f -size +0c 2>/dev/null",
-	"find var/log -type f -size +0c 2>/dev/null",
-}
+// KnownLogFilesCommand returns a shell command that lists the files in the
+// well-known log file locations of the built-in Cloud Foundry log profile,
+// one file per line relative to the root directory
+func KnownLogFilesCommand() string {
+	for _, profile := range BuiltinLogProfiles() {
+		if profile.Name == CloudFoundryLogProfileName {
+			return profile.LogFilesCommand()
+		}
+	}
 
-var cfgFinds = []string{
-	"find var/vcap/jobs -type f -size +0c 2>/dev/null",
-	"find etc/nginx -type f -size +0c 2>/dev/null",
-	"find opt/fissile -type f -size +0c 2>/dev/null",
+	return ""
 }
 
 const retrieveScript = `
@@ -90,17 +92,84 @@ func createDirectory(path string) error {
 	return nil
 }
 
+// RetrieveLogsOptions defines the settings for retrieving log files
+type RetrieveLogsOptions struct {
+	// ParallelDownloads is the number of parallel download jobs
+	ParallelDownloads int
+
+	// Target is the local directory to store the files in
+	Target string
+
+	// IncludeConfigFiles defines whether configuration files are downloaded
+	IncludeConfigFiles bool
+
+	// Filter restricts the pods from which the files are downloaded
+	Filter ListFilter
+
+	// Profiles define which files are downloaded from the pods, the first
+	// profile that matches a pod is used. In case no profiles are set, the
+	// built-in profiles are used.
+	Profiles []LogProfile
+}
+
+// ContainerLogsOptions defines the settings for streaming container logs
+type ContainerLogsOptions struct {
+	// Follow defines whether the stream is kept open for new log lines
+	Follow bool
+
+	// Previous defines whether the logs of the previous instance of the
+	// container are streamed, for example after it crashed
+	Previous bool
+
+	// Since restricts the logs to lines that are newer than the given
+	// duration, zero means all lines
+	Since time.Duration
+
+	// Context can be used to stop the streaming in addition to the context
+	// of the Havener
+	Context context.Context
+}
+
 // RetrieveLogs downloads log and configuration files from some well known location of all the pods
 // of all the namespaces and stored them in the local file system.
 func (h *Hvnr) RetrieveLogs(parallelDownloads int, target string, includeConfigFiles bool) error {
+	return h.RetrieveLogsWithOptions(RetrieveLogsOptions{
+		ParallelDownloads:  parallelDownloads,
+		Target:             target,
+		IncludeConfigFiles: includeConfigFiles,
+	})
+}
+
+// RetrieveLogsWithOptions downloads log and configuration files from some well
+// known location of all pods that match the filter of the provided options and
+// stores them in the local file system.
+func (h *Hvnr) RetrieveLogsWithOptions(options RetrieveLogsOptions) error {
+	var (
+		parallelDownloads  = options.ParallelDownloads
+		target             = options.Target
+		includeConfigFiles = options.IncludeConfigFiles
+	)
+
 	if absolute, err := filepath.Abs(target); err == nil {
 		target = absolute
 	}
 
+	var profiles = options.Profiles
+	if len(profiles) == 0 {
+		profiles = BuiltinLogProfiles()
+	}
+
+	for _, profile := range profiles {
+		if err := profile.Validate(); err != nil {
+			return err
+		}
+	}
+
 	type task struct {
 		assignment string
 		pod        *corev1.Pod
 		baseDir    string
+		profile    LogProfile
 	}
 
 	tasks := make(chan *task)
@@ -114,7 +183,7 @@ func (h *Hvnr) RetrieveLogs(parallelDownloads int, target string, includeConfigF
 			for task := range tasks {
 				switch task.assignment {
 				case "known-logs":
-					for _, err := range h.retrieveFilesFromPod(task.pod, task.baseDir, logFinds) {
+					for _, err := range h.retrieveFilesFromPod(task.pod, task.baseDir, task.profile.LogFilesCommand()) {
 						switch err {
 						case io.EOF, gzip.ErrHeader, gzip.ErrChecksum:
 							continue
@@ -126,7 +195,7 @@ func (h *Hvnr) RetrieveLogs(parallelDownloads int, target string, includeConfigF
 					}
 
 				case "config-files":
-					for _, err := range h.retrieveFilesFromPod(task.pod, task.baseDir, cfgFinds) {
+					for _, err := range h.retrieveFilesFromPod(task.pod, task.baseDir, task.profile.ConfigFilesCommand()) {
 						switch err {
 						case io.EOF, gzip.ErrHeader, gzip.ErrChecksum:
 							continue
@@ -157,8 +226,10 @@ func (h *Hvnr) RetrieveLogs(parallelDownloads int, target string, includeConfigF
 		}()
 	}
 
-	pods, err := h.ListPods()
+	pods, err := h.ListPodsWithOptions(options.Filter)
 	if err != nil {
+		close(tasks)
+		wg.Wait()
 		return err
 	}
 
@@ -203,20 +274,25 @@ func (h *Hvnr) RetrieveLogs(parallelDownloads int, target string, includeConfigF
 			baseDir:    filepath.Join(baseDir, pod.Name, "container-logs"),
 		}
 
-		if pod.Status.Phase == corev1.PodRunning {
-			// For running pods, download known log files
-			tasks <- &task{
-				assignment: "known-logs",
-				pod:        pod,
-				baseDir:    filepath.Join(baseDir, pod.Name, "container-filesystem"),
+		profile, ok := MatchingLogProfile(profiles, pod)
+		if ok && pod.Status.Phase == corev1.PodRunning {
+			// For running pods, download log files of the profile
+			if len(profile.Logs) > 0 {
+				tasks <- &task{
+					assignment: "known-logs",
+					pod:        pod,
+					baseDir:    filepath.Join(baseDir, pod.Name, "container-filesystem"),
+					profile:    profile,
+				}
 			}
 
-			// For running pods, download configuration file
-			if includeConfigFiles {
+			// For running pods, download configuration files of the profile
+			if includeConfigFiles && len(profile.Configs) > 0 {
 				tasks <- &task{
 					assignment: "config-files",
 					pod:        pod,
 					baseDir:    filepath.Join(baseDir, pod.Name, "container-filesystem"),
+					profile:    profile,
 				}
 			}
 		}
@@ -232,7 +308,7 @@ func (h *Hvnr) RetrieveLogs(parallelDownloads int, target string, includeConfigF
 	return nil
 }
 
-func (h *Hvnr) retrieveFilesFromPod(pod *corev1.Pod, baseDir string, findCommands []string) []error {
+func (h *Hvnr) retrieveFilesFromPod(pod *corev1.Pod, baseDir string, findCommand string) []error {
 	errors := []error{}
 
 	for _, container := range pod.Spec.Containers {
@@ -256,7 +332,7 @@ func (h *Hvnr) retrieveFilesFromPod(pod *corev1.Pod, baseDir string, findCommand
 					Command: []string{"/bin/sh", "-c",
 						fmt.Sprintf(
 							retrieveScript,
-							strings.Join(findCommands, "; "),
+							findCommand,
 						)},
 					Stdout: write,
 				},
@@ -296,6 +372,10 @@ func untar(inputStream io.Reader, targetPath string) error {
 			continue
 		}
 
+		if !filepath.IsLocal(header.Name) {
+			return fmt.Errorf("archive entry %s is outside of the target directory", header.Name)
+		}
+
 		target := filepath.Join(targetPath, header.Name)
 		switch header.Typeflag {
 		case tar.TypeDir: // directory entry
@@ -309,16 +389,19 @@ func untar(inputStream io.Reader, targetPath string) error {
 				return err
 			}
 
-			file, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR|os.O_TRUNC, os.FileMode(header.Mode))
 			if err != nil {
 				return err
 			}
 
 			if _, err := io.Copy(file, tarReader); err != nil {
+				file.Close()
 				return err
 			}
 
-			file.Close()
+			if err := file.Close(); err != nil {
+				return err
+			}
 		}
 	}
 }
@@ -422,3 +505,42 @@ func (h *Hvnr) saveDeploymentYAML(pod *corev1.Pod, baseDir string) error {
 		0644,
 	)
 }
+
+// StreamContainerLogs writes the logs of the given pod container to the
+// provided writer. In follow mode, it returns once the container terminates,
+// or the context of the Havener handle or the options is done.
+func (h *Hvnr) StreamContainerLogs(pod *corev1.Pod, container string, options ContainerLogsOptions, w io.Writer) error {
+	var logOptions = corev1.PodLogOptions{
+		Container: container,
+		Follow:    options.Follow,
+		Previous:  options.Previous,
+	}
+
+	if options.Since > 0 {
+		var seconds = int64(math.Ceil(options.Since.Seconds()))
+		logOptions.SinceSeconds = &seconds
+	}
+
+	var ctx = h.ctx
+	if options.Context != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+
+		stop := context.AfterFunc(options.Context, cancel)
+		defer stop()
+	}
+
+	readCloser, err := h.client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &logOptions).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open log stream of %s/%s/%s: %w", pod.Namespace, pod.Name, container, err)
+	}
+
+	defer readCloser.Close()
+
+	if _, err := io.Copy(w, readCloser); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to read log stream of %s/%s/%s: %w", pod.Namespace, pod.Name, container, err)
+	}
+
+	return nil
+}
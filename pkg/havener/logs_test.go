@@ -0,0 +1,59 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package havener_test
+
+import (
+	"bytes"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/homeport/havener/pkg/havener"
+	"github.com/homeport/havener/pkg/havener/havenertest"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("Streaming container logs", func() {
+	It("should request the logs of the container with the provided options", func() {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api-0"}}
+		hvnr := havenertest.NewHavener(pod)
+
+		var buf bytes.Buffer
+		Expect(hvnr.StreamContainerLogs(pod, "api", havener.ContainerLogsOptions{Follow: true, Since: 90 * time.Second}, &buf)).To(Succeed())
+		Expect(buf.String()).To(Equal("fake logs"))
+
+		var logOptions *corev1.PodLogOptions
+		for _, action := range hvnr.Clientset.Actions() {
+			if action.GetSubresource() == "log" {
+				logOptions = action.(k8stesting.GenericAction).GetValue().(*corev1.PodLogOptions)
+			}
+		}
+
+		Expect(logOptions).ToNot(BeNil())
+		Expect(logOptions.Container).To(Equal("api"))
+		Expect(logOptions.Follow).To(BeTrue())
+		Expect(logOptions.Previous).To(BeFalse())
+		Expect(logOptions.SinceSeconds).To(HaveValue(BeEquivalentTo(90)))
+	})
+})
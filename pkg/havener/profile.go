@@ -0,0 +1,254 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package havener
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// CloudFoundryLogProfileName is the name of the built-in log profile for
+// Cloud Foundry workloads
+const CloudFoundryLogProfileName = "cloudfoundry"
+
+// profilePathPattern restricts the paths of log profiles to absolute paths
+// that can be used in a shell command without quotes, so that glob patterns
+// in the path are expanded by the shell
+var profilePathPattern = regexp.MustCompile(`^/[A-Za-z0-9_./*?\[\]@%+=:,~-]*$`)
+
+// LogProfile is a named set of files that are collected from pods
+type LogProfile struct {
+	// Name is used to select the profile
+	Name string `yaml:"name"`
+
+	// Namespaces restricts the profile to pods in the given namespaces
+	Namespaces []string `yaml:"namespaces,omitempty"`
+
+	// Selector restricts the profile to pods that match the label selector
+	Selector string `yaml:"selector,omitempty"`
+
+	// MaxSize is the maximum size of a file, for example 10Mi, files that
+	// are bigger are not collected
+	MaxSize string `yaml:"maxSize,omitempty"`
+
+	// Logs are the locations of the log files
+	Logs []LogProfilePath `yaml:"logs,omitempty"`
+
+	// Configs are the locations of the configuration files
+	Configs []LogProfilePath `yaml:"configs,omitempty"`
+}
+
+// LogProfilePath is a location of files in a pod, which is a directory that
+// is searched recursively, or a glob pattern, for example /var/log/*/current
+type LogProfilePath struct {
+	// Path is the absolute path of the location
+	Path string `yaml:"path"`
+
+	// Include restricts the files to those with a name that matches one of
+	// the glob patterns, for example *.log
+	Include []string `yaml:"include,omitempty"`
+
+	// Exclude ignores files with a name that matches one of the glob patterns
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// BuiltinLogProfiles returns the log profiles that are available without a
+// configuration file, which is the profile for Cloud Foundry workloads
+func BuiltinLogProfiles() []LogProfile {
+	return []LogProfile{
+		{
+			Name: CloudFoundryLogProfileName,
+			Logs: []LogProfilePath{
+				{Path: "/var/vcap/sys", Include: []string{"*.log", "*.log.*"}},
+				{Path: "/var/vcap/monit"},
+				{Path: "/var/log"},
+			},
+			Configs: []LogProfilePath{
+				{Path: "/var/vcap/jobs"},
+				{Path: "/etc/nginx"},
+				{Path: "/opt/fissile"},
+			},
+		},
+	}
+}
+
+// LoadLogProfiles reads the log profiles from the given YAML file, which
+// contains a list of profiles under the key profiles
+func LoadLogProfiles(path string) ([]LogProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log profiles: %w", err)
+	}
+
+	var config struct {
+		Profiles []LogProfile `yaml:"profiles"`
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to parse log profiles in %s: %w", path, err)
+	}
+
+	var names = map[string]struct{}{}
+	for _, profile := range config.Profiles {
+		if err := profile.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid log profiles in %s: %w", path, err)
+		}
+
+		if _, ok := names[profile.Name]; ok {
+			return nil, fmt.Errorf("invalid log profiles in %s: profile %s is defined more than once", path, profile.Name)
+		}
+
+		names[profile.Name] = struct{}{}
+	}
+
+	return config.Profiles, nil
+}
+
+// Validate checks that the profile has a name, and that the selector, the
+// size limit, and all paths can be used
+func (p LogProfile) Validate() error {
+	if p.Name == "" {
+		return errors.New("profile without a name")
+	}
+
+	if _, err := labels.Parse(p.Selector); err != nil {
+		return fmt.Errorf("profile %s has an invalid selector: %w", p.Name, err)
+	}
+
+	if p.MaxSize != "" {
+		if _, err := resource.ParseQuantity(p.MaxSize); err != nil {
+			return fmt.Errorf("profile %s has an invalid maximum size: %w", p.Name, err)
+		}
+	}
+
+	for _, location := range append(append([]LogProfilePath{}, p.Logs...), p.Configs...) {
+		if !profilePathPattern.MatchString(location.Path) {
+			return fmt.Errorf("profile %s has an unsupported path %q, it needs to be absolute and must not contain spaces or quotes", p.Name, location.Path)
+		}
+	}
+
+	return nil
+}
+
+// Matches returns whether the profile applies to the given pod
+func (p LogProfile) Matches(pod *corev1.Pod) bool {
+	if len(p.Namespaces) > 0 && !contains(p.Namespaces, pod.Namespace) {
+		return false
+	}
+
+	selector, err := labels.Parse(p.Selector)
+	return err == nil && selector.Matches(labels.Set(pod.Labels))
+}
+
+func contains(list []string, value string) bool {
+	for _, entry := range list {
+		if entry == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchingLogProfile returns the first profile that applies to the pod
+func MatchingLogProfile(profiles []LogProfile, pod *corev1.Pod) (LogProfile, bool) {
+	for _, profile := range profiles {
+		if profile.Matches(pod) {
+			return profile, true
+		}
+	}
+
+	return LogProfile{}, false
+}
+
+// LogFilesCommand returns a shell command that lists the log files of the
+// profile, one file per line relative to the root directory
+func (p LogProfile) LogFilesCommand() string {
+	return strings.Join(p.findCommands(p.Logs), "; ")
+}
+
+// ConfigFilesCommand returns a shell command that lists the configuration
+// files of the profile, one file per line relative to the root directory
+func (p LogProfile) ConfigFilesCommand() string {
+	return strings.Join(p.findCommands(p.Configs), "; ")
+}
+
+// findCommands returns one find command per location, which are meant to be
+// run in the root directory and only list non-empty files
+func (p LogProfile) findCommands(locations []LogProfilePath) []string {
+	var sizeLimit string
+	if quantity, err := resource.ParseQuantity(p.MaxSize); err == nil && p.MaxSize != "" {
+		sizeLimit = fmt.Sprintf(" -size -%dc", quantity.Value()+1)
+	}
+
+	var result []string
+	for _, location := range locations {
+		var cmd strings.Builder
+		cmd.WriteString("find ")
+
+		if path := strings.TrimLeft(location.Path, "/"); path != "" {
+			cmd.WriteString(path)
+		} else {
+			cmd.WriteString(".")
+		}
+
+		cmd.WriteString(" -type f")
+
+		for i, pattern := range location.Include {
+			switch {
+			case len(location.Include) == 1:
+				fmt.Fprintf(&cmd, " -name %s", shellQuote(pattern))
+
+			case i == 0:
+				fmt.Fprintf(&cmd, ` \( -name %s`, shellQuote(pattern))
+
+			case i == len(location.Include)-1:
+				fmt.Fprintf(&cmd, ` -o -name %s \)`, shellQuote(pattern))
+
+			default:
+				fmt.Fprintf(&cmd, " -o -name %s", shellQuote(pattern))
+			}
+		}
+
+		for _, pattern := range location.Exclude {
+			fmt.Fprintf(&cmd, " ! -name %s", shellQuote(pattern))
+		}
+
+		cmd.WriteString(" -size +0c")
+		cmd.WriteString(sizeLimit)
+		cmd.WriteString(" 2>/dev/null")
+
+		result = append(result, cmd.String())
+	}
+
+	return result
+}
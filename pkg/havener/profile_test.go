@@ -0,0 +1,157 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+package havener_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/homeport/havener/pkg/havener"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Log profiles", func() {
+	// listFiles runs the command in the directory, which is used as the root
+	// directory, and returns the listed files
+	var listFiles = func(dir string, command string) []string {
+		cmd := exec.Command("/bin/sh", "-c", command)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		Expect(err).ToNot(HaveOccurred())
+		return strings.Fields(string(out))
+	}
+
+	var writeFile = func(path string, size int) {
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(strings.Repeat("x", size)), 0644)).To(Succeed())
+	}
+
+	It("should provide the built-in Cloud Foundry profile", func() {
+		profiles := havener.BuiltinLogProfiles()
+		Expect(profiles).To(HaveLen(1))
+		Expect(profiles[0].Name).To(Equal(havener.CloudFoundryLogProfileName))
+		Expect(profiles[0].Validate()).To(Succeed())
+
+		Expect(profiles[0].LogFilesCommand()).To(Equal(
+			`find var/vcap/sys -type f \( -name '*.log' -o -name '*.log.*' \) -size +0c 2>/dev/null; ` +
+				"find var/vcap/monit -type f -size +0c 2>/dev/null; " +
+				"find var/log -type f -size +0c 2>/dev/null",
+		))
+
+		Expect(profiles[0].ConfigFilesCommand()).To(Equal(
+			"find var/vcap/jobs -type f -size +0c 2>/dev/null; " +
+				"find etc/nginx -type f -size +0c 2>/dev/null; " +
+				"find opt/fissile -type f -size +0c 2>/dev/null",
+		))
+	})
+
+	It("should only list files that match the patterns and size limit of the profile", func() {
+		dir := GinkgoT().TempDir()
+		writeFile(filepath.Join(dir, "app", "1", "log", "app.log"), 10)
+		writeFile(filepath.Join(dir, "app", "1", "log", "empty.log"), 0)
+		writeFile(filepath.Join(dir, "app", "1", "log", "huge.log"), 2048)
+		writeFile(filepath.Join(dir, "app", "1", "log", "debug.log"), 10)
+		writeFile(filepath.Join(dir, "app", "1", "log", "app.out"), 10)
+		writeFile(filepath.Join(dir, "app", "2", "log", "app.log"), 1024)
+
+		profile := havener.LogProfile{
+			Name:    "app",
+			MaxSize: "1Ki",
+			Logs: []havener.LogProfilePath{
+				{Path: "/app/*/log", Include: []string{"*.log"}, Exclude: []string{"debug.*"}},
+			},
+		}
+
+		Expect(profile.Validate()).To(Succeed())
+		Expect(listFiles(dir, profile.LogFilesCommand())).To(ConsistOf(
+			"app/1/log/app.log",
+			"app/2/log/app.log",
+		))
+	})
+
+	It("should apply to the pods in the namespaces that match the selector", func() {
+		pod := func(namespace string, app string) *corev1.Pod {
+			return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "pod", Labels: map[string]string{"app": app}}}
+		}
+
+		profile := havener.LogProfile{Name: "postgres", Namespaces: []string{"db"}, Selector: "app=postgres"}
+		Expect(profile.Matches(pod("db", "postgres"))).To(BeTrue())
+		Expect(profile.Matches(pod("db", "mysql"))).To(BeFalse())
+		Expect(profile.Matches(pod("default", "postgres"))).To(BeFalse())
+		Expect(havener.LogProfile{Name: "all"}.Matches(pod("default", "mysql"))).To(BeTrue())
+	})
+
+	Context("loading profiles from a file", func() {
+		var load = func(content string) ([]havener.LogProfile, error) {
+			path := filepath.Join(GinkgoT().TempDir(), "profiles.yml")
+			Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+			return havener.LoadLogProfiles(path)
+		}
+
+		It("should load the profiles", func() {
+			profiles, err := load(`---
+profiles:
+- name: postgres
+  namespaces: [db]
+  selector: app=postgres
+  maxSize: 50Mi
+  logs:
+  - path: /var/lib/postgresql/data/log
+    include: ["*.log"]
+  configs:
+  - path: /var/lib/postgresql/data
+    include: ["*.conf"]
+    exclude: ["*.auto.conf"]
+`)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(profiles).To(Equal([]havener.LogProfile{{
+				Name:       "postgres",
+				Namespaces: []string{"db"},
+				Selector:   "app=postgres",
+				MaxSize:    "50Mi",
+				Logs:       []havener.LogProfilePath{{Path: "/var/lib/postgresql/data/log", Include: []string{"*.log"}}},
+				Configs:    []havener.LogProfilePath{{Path: "/var/lib/postgresql/data", Include: []string{"*.conf"}, Exclude: []string{"*.auto.conf"}}},
+			}}))
+		})
+
+		It("should fail for unknown fields", func() {
+			_, err := load("profiles:\n- name: java\n  paths: [/opt/app/logs]\n")
+			Expect(err).To(MatchError(ContainSubstring("field paths not found")))
+		})
+
+		It("should fail for invalid profiles", func() {
+			_, err := load("profiles:\n- name: java\n  logs:\n  - path: opt/app/logs\n")
+			Expect(err).To(MatchError(ContainSubstring(`unsupported path "opt/app/logs"`)))
+
+			_, err = load("profiles:\n- name: java\n  maxSize: lots\n")
+			Expect(err).To(MatchError(ContainSubstring("invalid maximum size")))
+
+			_, err = load("profiles:\n- name: java\n- name: java\n")
+			Expect(err).To(MatchError(ContainSubstring("profile java is defined more than once")))
+		})
+	})
+})
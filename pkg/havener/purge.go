@@ -26,7 +26,7 @@ import (
 
 // PurgePod removes the pod in the given namespace.
 func (h *Hvnr) PurgePod(namespace string, podName string, gracePeriodSeconds int64, propagationPolicy metav1.DeletionPropagation) error {
-	logf(Verbose, "Deleting pod %s in namespace %s", podName, namespace)
+	h.logger.Info("Deleting pod", "namespace", namespace, "pod", podName)
 	return h.client.CoreV1().Pods(namespace).Delete(h.ctx,
 		podName,
 		metav1.DeleteOptions{
@@ -33,6 +33,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
@@ -113,12 +114,12 @@ func (h *Hvnr) TopDetails() (*TopDetails, error) {
 		Containers: map[string]map[string]map[string]ContainerDetails{},
 	}
 
-	nodeList, err := h.client.CoreV1().Nodes().List(h.ctx, metav1.ListOptions{})
+	nodes, err := h.ListNodes()
 	if err != nil {
 		return nil, err
 	}
 
-	for _, node := range nodeList.Items {
+	for _, node := range nodes {
 		result.Nodes[node.Name] = NodeDetails{
 			TotalCPU:    int64(node.Status.Capacity.Cpu().MilliValue()),
 			TotalMemory: int64(node.Status.Capacity.Memory().Value()),
@@ -155,10 +156,10 @@ func (h *Hvnr) TopDetails() (*TopDetails, error) {
 
 	var (
 		wg      sync.WaitGroup
-		errChan = make(chan error)
+		errChan = make(chan error, 2+len(nodes))
 	)
 
-	wg.Add(2 + len(nodeList.Items))
+	wg.Add(2 + len(nodes))
 
 	// Reach out to Kubernetes metrics service to get node details
 	go func() {
@@ -166,7 +167,7 @@ func (h *Hvnr) TopDetails() (*TopDetails, error) {
 
 		nodeMetricsJSON, err := h.client.CoreV1().RESTClient().Get().AbsPath("apis/metrics.k8s.io/v1beta1/nodes").DoRaw(h.ctx)
 		if err != nil {
-			errChan <- err
+			errChan <- metricsError(err)
 			return
 		}
 
@@ -192,7 +193,7 @@ func (h *Hvnr) TopDetails() (*TopDetails, error) {
 
 		podMetricsJSON, err := h.client.CoreV1().RESTClient().Get().AbsPath("apis/metrics.k8s.io/v1beta1/pods").DoRaw(h.ctx)
 		if err != nil {
-			errChan <- err
+			errChan <- metricsError(err)
 			return
 		}
 
@@ -214,7 +215,7 @@ func (h *Hvnr) TopDetails() (*TopDetails, error) {
 		result.Unlock()
 	}()
 
-	for _, node := range nodeList.Items {
+	for _, node := range nodes {
 		go func(node corev1.Node) {
 			defer wg.Done()
 
@@ -244,10 +245,7 @@ func (h *Hvnr) TopDetails() (*TopDetails, error) {
 			)
 
 			if err != nil {
-				errChan <- fmt.Errorf("%s: %w",
-					err.Error(),
-					errors.New(stderr.String()),
-				)
+				errChan <- fmt.Errorf("%w: %s", err, stderr.String())
 			}
 
 			result.Lock()
@@ -273,6 +271,16 @@ func (h *Hvnr) TopDetails() (*TopDetails, error) {
 	return &result, nil
 }
 
+// metricsError marks errors of a missing metrics API (the API server does not
+// know the metrics API, or the metrics server does not respond)
+func metricsError(err error) error {
+	if apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err) {
+		return fmt.Errorf("%w: %w", ErrMetricsAPIUnavailable, err)
+	}
+
+	return err
+}
+
 func parseQuantity(input string) *resource.Quantity {
 	quantity := resource.MustParse(input)
 	return &quantity